@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
@@ -25,96 +26,245 @@ func main() {
 
 	log.SetLevel(*logLevel)
 
+	var burnRateWindows []time.Duration
+	if cfg.BurnRateMetrics {
+		burnRateWindows, err = cfg.BurnRateWindowDurations()
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	constLabels, err := cfg.ParseConstLabels()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	targetLabels, err := cfg.ParseTargetLabels()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	registerer := prometheus.WrapRegistererWith(constLabels, prometheus.DefaultRegisterer)
+	// register registers a collector, name is the prefix of its up series.
+	register := func(name string, c prometheus.Collector) {
+		if cfg.UpMetrics {
+			c = collector.WithUpSeries(c, name)
+		}
+		registerer.MustRegister(collector.WithTargetLabels(c, targetLabels))
+	}
+
+	// A single price feed is shared so every collector uses the same cached
+	// CoinGecko prices.
+	priceFeed := collector.NewPriceFeed(cfg)
+
 	if cfg.WalletAddresses != "" {
 		walletCollector := collector.WalletBalanceCollector{
 			Cfg: cfg,
 		}
+		if cfg.BalanceUSDMetrics {
+			walletCollector.Prices = priceFeed
+		}
 
-		go prometheus.MustRegister(walletCollector)
+		go register("cosmos_wallet", walletCollector)
 	}
 
 	if cfg.ValidatorMetrics {
 		validatorCollector := collector.ValidatorsCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(validatorCollector)
+		go register("validator", validatorCollector)
+	}
+
+	if cfg.WardenMetrics {
+		spaceIDs, spaceIDsErr := cfg.ParseWardenSpaceIDs()
+		if spaceIDsErr != nil {
+			log.Fatal(spaceIDsErr.Error())
+		}
+
+		// Keys are counted in the background as paging through all of them
+		// can take longer than a scrape
+		keyCounter := collector.NewKeyCounter(cfg)
+		if loadErr := keyCounter.Load(); loadErr != nil {
+			log.Fatal(loadErr.Error())
+		}
+		go keyCounter.Run(context.Background())
+
+		wardenCollector := collector.WardenCollector{
+			Cfg:      cfg,
+			Requests: collector.NewRequestTracker(),
+			Keys:     keyCounter,
+			Actions:  collector.NewActionCounter(),
+			SpaceIDs: spaceIDs,
+		}
+		go register("warden", wardenCollector)
 	}
 
 	if cfg.MintMetrics {
 		mintCollector := collector.MintCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(mintCollector)
+		go register("mint", mintCollector)
+	}
+
+	if cfg.CometBFTMetrics {
+		cometBFTCollector := collector.CometBFTCollector{
+			Cfg: cfg,
+		}
+		go register("cometbft", cometBFTCollector)
+	}
+
+	if cfg.NodeMetrics {
+		nodes, nodesErr := cfg.ParseNodeEndpoints()
+		if nodesErr != nil {
+			log.Fatal(nodesErr.Error())
+		}
+
+		nodesCollector := collector.NodesCollector{
+			Cfg:   cfg,
+			Nodes: nodes,
+		}
+		go register("cosmos_node", nodesCollector)
+	}
+
+	if cfg.IBCMetrics {
+		counterparties, counterpartiesErr := cfg.ParseIBCCounterpartyEndpoints()
+		if counterpartiesErr != nil {
+			log.Fatal(counterpartiesErr.Error())
+		}
+
+		ibcCollector := collector.IBCCollector{
+			Cfg:            cfg,
+			Counterparties: counterparties,
+		}
+		go register("ibc", ibcCollector)
+	}
+
+	if cfg.OracleMetrics {
+		oracleCollector := collector.OracleCollector{
+			Cfg: cfg,
+		}
+		go register("oracle", oracleCollector)
+	}
+
+	if cfg.EVMMetrics {
+		evmCollector := collector.EVMCollector{
+			Cfg: cfg,
+		}
+		go register("evm", evmCollector)
 	}
 
 	if cfg.VeniceMetrics {
 		veniceCollector := collector.VeniceCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(veniceCollector)
+		if cfg.BurnRateMetrics {
+			veniceCollector.History = collector.NewBalanceHistory(burnRateWindows)
+		}
+		go register("venice", veniceCollector)
 	}
 
 	if cfg.MessariMetrics {
 		messariCollector := collector.MessariCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(messariCollector)
+		go register("messari", messariCollector)
 	}
 
 	if cfg.BaseMetrics {
 		baseCollector := collector.BaseCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(baseCollector)
+		if cfg.BalanceUSDMetrics {
+			baseCollector.Prices = priceFeed
+		}
+		go register("base_wallet", baseCollector)
 	}
 
 	if cfg.BnbMetrics {
 		bnbCollector := collector.BnbCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(bnbCollector)
+		if cfg.BalanceUSDMetrics {
+			bnbCollector.Prices = priceFeed
+		}
+		go register("bnb_wallet", bnbCollector)
 	}
 
 	if cfg.CoinGeckoMetrics {
 		coinGeckoCollector := collector.CoinGeckoCollector{
-			Cfg: cfg,
+			Cfg:    cfg,
+			Prices: priceFeed,
+		}
+		go register("coingecko", coinGeckoCollector)
+	}
+
+	if cfg.PortfolioMetrics {
+		walletGroups, groupsErr := cfg.ParseWalletGroups()
+		if groupsErr != nil {
+			log.Fatal(groupsErr.Error())
 		}
-		go prometheus.MustRegister(coinGeckoCollector)
+
+		portfolioCollector := collector.PortfolioCollector{
+			Cfg:    cfg,
+			Groups: walletGroups,
+			Prices: priceFeed,
+		}
+		go register("portfolio", portfolioCollector)
 	}
 
 	if cfg.XAIMetrics {
 		xaiCollector := collector.XAICollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(xaiCollector)
+		if cfg.BurnRateMetrics {
+			xaiCollector.History = collector.NewBalanceHistory(burnRateWindows)
+		}
+		go register("xai", xaiCollector)
 	}
 
 	if cfg.OpenAIMetrics {
 		openAICollector := collector.OpenAICollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(openAICollector)
+		go register("openai", openAICollector)
 	}
 
 	if cfg.TavilyMetrics {
 		tavilyCollector := collector.TavilyCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(tavilyCollector)
+		go register("tavily", tavilyCollector)
 	}
 
 	if cfg.OpenRouterMetrics {
 		openRouterCollector := collector.OpenRouterCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(openRouterCollector)
+		if cfg.BurnRateMetrics {
+			openRouterCollector.History = collector.NewBalanceHistory(burnRateWindows)
+		}
+		go register("openrouter", openRouterCollector)
 	}
 
 	if cfg.ComposioMetrics {
 		composioCollector := collector.ComposioCollector{
 			Cfg: cfg,
 		}
-		go prometheus.MustRegister(composioCollector)
+		go register("composio", composioCollector)
+	}
+
+	if cfg.AnthropicMetrics {
+		anthropicCollector := collector.AnthropicCollector{
+			Cfg: cfg,
+		}
+		go register("anthropic", anthropicCollector)
+	}
+
+	if cfg.GeminiMetrics {
+		geminiCollector := collector.GeminiCollector{
+			Cfg: cfg,
+		}
+		go register("gemini", geminiCollector)
 	}
 
 	mux := http.NewServeMux()
@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	act "github.com/warden-protocol/wardenprotocol/warden/x/act/types/v1beta1"
+
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+)
+
+const actionPageLimit = 1000
+
+type pendingAction struct {
+	msgType   string
+	createdAt time.Time
+}
+
+// ActionCounter counts x/act actions by status and message type between
+// scrapes. Completed, revoked and timed out actions never change again, so
+// they are counted once: a scrape only fetches the actions created since the
+// previous one and looks up the status of the actions that were still
+// pending. It is shared between scrapes, so collectors hold it by pointer.
+type ActionCounter struct {
+	mu     sync.Mutex
+	nextID uint64
+	// resolved counts the actions that are no longer pending by message type.
+	resolved map[string]map[act.ActionStatus]uint64
+	pending  map[uint64]pendingAction
+}
+
+func NewActionCounter() *ActionCounter {
+	return &ActionCounter{
+		resolved: make(map[string]map[act.ActionStatus]uint64),
+		pending:  make(map[uint64]pendingAction),
+	}
+}
+
+// update looks up the pending actions and fetches the new ones. Every action
+// is counted as soon as it is fetched, so a failed update resumes where it
+// stopped.
+func (a *ActionCounter) update(ctx context.Context, client grpc.Client) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for id, action := range a.pending {
+		status, err := client.ActionStatus(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if status != act.ActionStatus_ACTION_STATUS_PENDING {
+			a.resolve(action.msgType, status)
+			delete(a.pending, id)
+		}
+	}
+
+	for {
+		actions, more, err := client.ActionsPage(ctx, a.nextID, actionPageLimit)
+		if err != nil {
+			return err
+		}
+
+		for _, action := range actions {
+			// Skip actions before the next id in case a page overlaps
+			if action.Id < a.nextID {
+				continue
+			}
+
+			msgType := unknownGroup
+			if action.Msg != nil && action.Msg.TypeUrl != "" {
+				msgType = action.Msg.TypeUrl
+			}
+
+			if action.Status == act.ActionStatus_ACTION_STATUS_PENDING {
+				a.pending[action.Id] = pendingAction{msgType: msgType, createdAt: action.CreatedAt}
+			} else {
+				a.resolve(msgType, action.Status)
+			}
+			a.nextID = action.Id + 1
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
+
+// resolve counts an action that is no longer pending, the caller must hold mu.
+func (a *ActionCounter) resolve(msgType string, status act.ActionStatus) {
+	if a.resolved[msgType] == nil {
+		a.resolved[msgType] = make(map[act.ActionStatus]uint64)
+	}
+	a.resolved[msgType][status]++
+}
+
+// counts returns the number of actions by message type and status, and the
+// creation time of the oldest pending action, zero without pending actions.
+func (a *ActionCounter) counts() (map[string]map[act.ActionStatus]uint64, time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	counts := make(map[string]map[act.ActionStatus]uint64, len(a.resolved))
+	for msgType, statusCounts := range a.resolved {
+		counts[msgType] = make(map[act.ActionStatus]uint64, len(statusCounts))
+		for status, count := range statusCounts {
+			counts[msgType][status] = count
+		}
+	}
+
+	var oldest time.Time
+	for _, action := range a.pending {
+		if counts[action.msgType] == nil {
+			counts[action.msgType] = make(map[act.ActionStatus]uint64)
+		}
+		counts[action.msgType][act.ActionStatus_ACTION_STATUS_PENDING]++
+
+		if oldest.IsZero() || action.createdAt.Before(oldest) {
+			oldest = action.createdAt
+		}
+	}
+
+	return counts, oldest
+}
@@ -0,0 +1,382 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	http "github.com/warden-protocol/warden-exporter/pkg/http"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	anthropicCostMetricName        = "anthropic_cost"
+	anthropicCostByModelMetricName = "anthropic_cost_by_model"
+	anthropicUsageTokensMetricName = "anthropic_usage_tokens"
+	anthropicAPIVersion            = "2023-06-01"
+	anthropicMaxPages              = 50
+	anthropicDefaultWorkspace      = "default"
+)
+
+type AnthropicCostReportResponse struct {
+	Data []struct {
+		StartingAt string `json:"starting_at"`
+		EndingAt   string `json:"ending_at"`
+		Results    []struct {
+			Currency    string  `json:"currency"`
+			Amount      string  `json:"amount"`
+			WorkspaceID *string `json:"workspace_id"`
+			Description *string `json:"description"`
+			CostType    *string `json:"cost_type"`
+			Model       *string `json:"model"`
+		} `json:"results"`
+	} `json:"data"`
+	HasMore  bool    `json:"has_more"`
+	NextPage *string `json:"next_page"`
+}
+
+type AnthropicUsageReportResponse struct {
+	Data []struct {
+		StartingAt string `json:"starting_at"`
+		EndingAt   string `json:"ending_at"`
+		Results    []struct {
+			UncachedInputTokens int64 `json:"uncached_input_tokens"`
+			CacheCreation       struct {
+				Ephemeral1hInputTokens int64 `json:"ephemeral_1h_input_tokens"`
+				Ephemeral5mInputTokens int64 `json:"ephemeral_5m_input_tokens"`
+			} `json:"cache_creation"`
+			CacheReadInputTokens int64   `json:"cache_read_input_tokens"`
+			OutputTokens         int64   `json:"output_tokens"`
+			WorkspaceID          *string `json:"workspace_id"`
+			Model                *string `json:"model"`
+		} `json:"results"`
+	} `json:"data"`
+	HasMore  bool    `json:"has_more"`
+	NextPage *string `json:"next_page"`
+}
+
+type anthropicGroupKey struct {
+	model       string
+	workspaceID string
+}
+
+type anthropicCosts struct {
+	total     float64
+	currency  string
+	breakdown map[anthropicGroupKey]float64
+}
+
+type anthropicTokens struct {
+	uncachedInput float64
+	cacheCreation float64
+	cacheRead     float64
+	output        float64
+}
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	anthropicCost = prometheus.NewDesc(
+		anthropicCostMetricName,
+		"Returns Anthropic API month-to-date costs",
+		[]string{
+			"account",
+			"currency",
+			"status",
+		},
+		nil,
+	)
+
+	anthropicCostByModel = prometheus.NewDesc(
+		anthropicCostByModelMetricName,
+		"Returns Anthropic API month-to-date costs by model and workspace",
+		[]string{
+			"account",
+			"model",
+			"workspace_id",
+			"currency",
+			"status",
+		},
+		nil,
+	)
+
+	anthropicUsageTokens = prometheus.NewDesc(
+		anthropicUsageTokensMetricName,
+		"Returns Anthropic API tokens used today (UTC) by model, workspace and token type",
+		[]string{
+			"account",
+			"model",
+			"workspace_id",
+			"type",
+			"status",
+		},
+		nil,
+	)
+)
+
+type AnthropicCollector struct {
+	Cfg config.Config
+}
+
+func (a AnthropicCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- anthropicCost
+	ch <- anthropicCostByModel
+	ch <- anthropicUsageTokens
+}
+
+func (a AnthropicCollector) Collect(ch chan<- prometheus.Metric) {
+	a.collectFailures(ch)
+}
+
+// collectFailures reports the accounts with failed requests, some of which,
+// like the usage breakdown, leave no series behind.
+func (a AnthropicCollector) collectFailures(ch chan<- prometheus.Metric) []string {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(a.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	var failed []string
+	for _, account := range config.ParseAPIAccounts(a.Cfg.AnthropicAdminKey) {
+		if errors := a.collectAccount(ctx, ch, account); len(errors) > 0 {
+			failed = append(failed, account.Name)
+		}
+	}
+
+	return failed
+}
+
+func (a AnthropicCollector) collectAccount(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+) []string {
+	var errors []string
+
+	errors = a.collectCostMetrics(ctx, ch, account, errors)
+	errors = a.collectUsageMetrics(ctx, ch, account, errors)
+
+	if len(errors) > 0 {
+		log.Info(fmt.Sprintf("Anthropic metrics collection for account %s completed with errors: %v", account.Name, errors))
+	} else {
+		log.Info(fmt.Sprintf("Anthropic metrics collection for account %s completed successfully", account.Name))
+	}
+
+	return errors
+}
+
+func (a AnthropicCollector) collectCostMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+	errors []string,
+) []string {
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	costStatus := successStatus
+	costs, err := a.anthropicCollectCosts(ctx, account.Key, monthStart, now)
+	if err != nil {
+		log.Error(fmt.Sprintf("error collecting Anthropic monthly costs: %s", err))
+		errors = append(errors, "monthly costs")
+		costStatus = errorStatus
+		costs = anthropicCosts{currency: "USD"}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		anthropicCost,
+		prometheus.GaugeValue,
+		costs.total,
+		[]string{account.Name, costs.currency, costStatus}...,
+	)
+
+	for key, value := range costs.breakdown {
+		ch <- prometheus.MustNewConstMetric(
+			anthropicCostByModel,
+			prometheus.GaugeValue,
+			value,
+			[]string{account.Name, key.model, key.workspaceID, costs.currency, costStatus}...,
+		)
+	}
+
+	return errors
+}
+
+func (a AnthropicCollector) collectUsageMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+	errors []string,
+) []string {
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	usage, err := a.anthropicCollectUsage(ctx, account.Key, dayStart, now)
+	if err != nil {
+		log.Error(fmt.Sprintf("error collecting Anthropic daily usage: %s", err))
+		return append(errors, "daily usage")
+	}
+
+	for key, tokens := range usage {
+		tokenTypes := []struct {
+			tokenType string
+			value     float64
+		}{
+			{"uncached_input", tokens.uncachedInput},
+			{"cache_creation", tokens.cacheCreation},
+			{"cache_read", tokens.cacheRead},
+			{"output", tokens.output},
+		}
+		for _, t := range tokenTypes {
+			ch <- prometheus.MustNewConstMetric(
+				anthropicUsageTokens,
+				prometheus.GaugeValue,
+				t.value,
+				[]string{account.Name, key.model, key.workspaceID, t.tokenType, successStatus}...,
+			)
+		}
+	}
+
+	return errors
+}
+
+func (a AnthropicCollector) anthropicCollectCosts(
+	ctx context.Context,
+	apiKey string,
+	startTime, endTime time.Time,
+) (anthropicCosts, error) {
+	query := url.Values{}
+	query.Set("starting_at", startTime.Format(time.RFC3339))
+	query.Set("ending_at", endTime.Format(time.RFC3339))
+	query.Add("group_by[]", "workspace_id")
+	query.Add("group_by[]", "description")
+
+	costs := anthropicCosts{
+		currency:  "USD",
+		breakdown: make(map[anthropicGroupKey]float64),
+	}
+
+	for range anthropicMaxPages {
+		data, err := a.anthropicGet(ctx, apiKey, "/organizations/cost_report", query)
+		if err != nil {
+			return anthropicCosts{}, err
+		}
+
+		var resp AnthropicCostReportResponse
+		if err = json.Unmarshal(data, &resp); err != nil {
+			return anthropicCosts{}, fmt.Errorf("error decoding response: %w", err)
+		}
+
+		for _, bucket := range resp.Data {
+			for _, res := range bucket.Results {
+				// Amounts are reported in the lowest currency unit, e.g. cents.
+				cents, parseErr := strconv.ParseFloat(res.Amount, 64)
+				if parseErr != nil {
+					log.Error(fmt.Sprintf("error parsing amount value '%s': %s", res.Amount, parseErr))
+					continue
+				}
+				amount := cents / 100.0
+
+				if res.Currency != "" {
+					costs.currency = strings.ToUpper(res.Currency)
+				}
+				costs.total += amount
+
+				key := anthropicGroupKey{
+					model:       groupValue(res.Model),
+					workspaceID: anthropicWorkspace(res.WorkspaceID),
+				}
+				costs.breakdown[key] += amount
+			}
+		}
+
+		if !resp.HasMore || resp.NextPage == nil || *resp.NextPage == "" {
+			return costs, nil
+		}
+		query.Set("page", *resp.NextPage)
+	}
+
+	return anthropicCosts{}, fmt.Errorf("cost report exceeded %d pages", anthropicMaxPages)
+}
+
+func (a AnthropicCollector) anthropicCollectUsage(
+	ctx context.Context,
+	apiKey string,
+	startTime, endTime time.Time,
+) (map[anthropicGroupKey]anthropicTokens, error) {
+	query := url.Values{}
+	query.Set("starting_at", startTime.Format(time.RFC3339))
+	query.Set("ending_at", endTime.Format(time.RFC3339))
+	query.Set("bucket_width", "1d")
+	query.Add("group_by[]", "model")
+	query.Add("group_by[]", "workspace_id")
+
+	usage := make(map[anthropicGroupKey]anthropicTokens)
+
+	for range anthropicMaxPages {
+		data, err := a.anthropicGet(ctx, apiKey, "/organizations/usage_report/messages", query)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp AnthropicUsageReportResponse
+		if err = json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("error decoding response: %w", err)
+		}
+
+		for _, bucket := range resp.Data {
+			for _, res := range bucket.Results {
+				key := anthropicGroupKey{
+					model:       groupValue(res.Model),
+					workspaceID: anthropicWorkspace(res.WorkspaceID),
+				}
+
+				tokens := usage[key]
+				tokens.uncachedInput += float64(res.UncachedInputTokens)
+				tokens.cacheCreation += float64(res.CacheCreation.Ephemeral1hInputTokens +
+					res.CacheCreation.Ephemeral5mInputTokens)
+				tokens.cacheRead += float64(res.CacheReadInputTokens)
+				tokens.output += float64(res.OutputTokens)
+				usage[key] = tokens
+			}
+		}
+
+		if !resp.HasMore || resp.NextPage == nil || *resp.NextPage == "" {
+			return usage, nil
+		}
+		query.Set("page", *resp.NextPage)
+	}
+
+	return nil, fmt.Errorf("usage report exceeded %d pages", anthropicMaxPages)
+}
+
+func (a AnthropicCollector) anthropicGet(
+	ctx context.Context,
+	apiKey, path string,
+	query url.Values,
+) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s%s?%s", a.Cfg.AnthropicAPIURL, path, query.Encode())
+	headers := map[string]string{
+		"x-api-key":         apiKey,
+		"anthropic-version": anthropicAPIVersion,
+	}
+
+	return http.GetRequestWithHeaders(ctx, reqURL, headers, a.Cfg.HTTPTimeout)
+}
+
+// anthropicWorkspace returns the workspace id, which the Anthropic API
+// reports as null for the default workspace.
+func anthropicWorkspace(v *string) string {
+	if v == nil || *v == "" {
+		return anthropicDefaultWorkspace
+	}
+
+	return *v
+}
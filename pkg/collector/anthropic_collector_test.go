@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+const testAnthropicKey = "sk-ant-admin-test"
+
+// newAnthropicServer serves the Anthropic admin API fixtures from testdata and
+// fails the test on requests that are not authenticated like the real API.
+func newAnthropicServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != testAnthropicKey {
+			t.Errorf("unexpected x-api-key header %q", r.Header.Get("x-api-key"))
+		}
+		if r.Header.Get("anthropic-version") != anthropicAPIVersion {
+			t.Errorf("unexpected anthropic-version header %q", r.Header.Get("anthropic-version"))
+		}
+
+		var fixture string
+		switch r.URL.Path {
+		case "/organizations/cost_report":
+			fixture = "anthropic_cost_report_page1.json"
+			if r.URL.Query().Get("page") == "page_2" {
+				fixture = "anthropic_cost_report_page2.json"
+			}
+		case "/organizations/usage_report/messages":
+			fixture = "anthropic_usage_report.json"
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Errorf("error reading fixture: %s", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+func newTestAnthropicCollector(serverURL string) AnthropicCollector {
+	return AnthropicCollector{
+		Cfg: config.Config{
+			AnthropicAdminKey: "prod:" + testAnthropicKey,
+			AnthropicAPIURL:   serverURL,
+			HTTPTimeout:       5,
+			Timeout:           5,
+		},
+	}
+}
+
+// TestAnthropicCollectCosts tests that cost report pages are followed and
+// amounts in cents are converted and grouped by model and workspace.
+func TestAnthropicCollectCosts(t *testing.T) {
+	server := newAnthropicServer(t)
+	defer server.Close()
+
+	a := newTestAnthropicCollector(server.URL)
+	costs, err := a.anthropicCollectCosts(t.Context(), testAnthropicKey, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("anthropicCollectCosts() error = %s", err)
+	}
+
+	if costs.total != 23 {
+		t.Errorf("total = %v, want 23", costs.total)
+	}
+
+	sonnet := anthropicGroupKey{model: "claude-sonnet-4-20250514", workspaceID: "wrkspc_agents"}
+	if costs.breakdown[sonnet] != 20 {
+		t.Errorf("breakdown[%v] = %v, want 20", sonnet, costs.breakdown[sonnet])
+	}
+
+	search := anthropicGroupKey{model: unknownGroup, workspaceID: anthropicDefaultWorkspace}
+	if costs.breakdown[search] != 3 {
+		t.Errorf("breakdown[%v] = %v, want 3", search, costs.breakdown[search])
+	}
+}
+
+// TestAnthropicCollectUsage tests that token usage is grouped by model and
+// workspace and cache creation tokens of both TTLs are summed.
+func TestAnthropicCollectUsage(t *testing.T) {
+	server := newAnthropicServer(t)
+	defer server.Close()
+
+	a := newTestAnthropicCollector(server.URL)
+	usage, err := a.anthropicCollectUsage(t.Context(), testAnthropicKey, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("anthropicCollectUsage() error = %s", err)
+	}
+
+	sonnet := usage[anthropicGroupKey{model: "claude-sonnet-4-20250514", workspaceID: "wrkspc_agents"}]
+	expected := anthropicTokens{uncachedInput: 1500, cacheCreation: 150, cacheRead: 200, output: 500}
+	if sonnet != expected {
+		t.Errorf("sonnet usage = %+v, want %+v", sonnet, expected)
+	}
+
+	haiku := usage[anthropicGroupKey{model: "claude-3-5-haiku-20241022", workspaceID: anthropicDefaultWorkspace}]
+	if haiku.output != 20 {
+		t.Errorf("haiku output tokens = %v, want 20", haiku.output)
+	}
+}
+
+// TestAnthropicCollect tests that a full scrape emits the cost total, the cost
+// breakdown and the token usage series.
+func TestAnthropicCollect(t *testing.T) {
+	server := newAnthropicServer(t)
+	defer server.Close()
+
+	a := newTestAnthropicCollector(server.URL)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		a.Collect(ch)
+		close(ch)
+	}()
+
+	count := 0
+	for range ch {
+		count++
+	}
+
+	// 1 total + 2 cost breakdown series + 2 groups * 4 token types.
+	if count != 11 {
+		t.Errorf("Collect() emitted %d metrics, want 11", count)
+	}
+}
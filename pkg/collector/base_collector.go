@@ -14,27 +14,45 @@ import (
 )
 
 const (
-	baseBalanceMetricName = "base_wallet_balance"
+	baseBalanceMetricName    = "base_wallet_balance"
+	baseBalanceUSDMetricName = "base_wallet_balance_usd"
 )
 
 //nolint:gochecknoglobals // this is needed as it's used in multiple places
-var baseBalance = prometheus.NewDesc(
-	baseBalanceMetricName,
-	"Returns the wallet balance on Base blockchain",
-	[]string{
-		"account",
-		"symbol",
-		"status",
-	},
-	nil,
+var (
+	baseBalance = prometheus.NewDesc(
+		baseBalanceMetricName,
+		"Returns the wallet balance on Base blockchain",
+		[]string{
+			"account",
+			"symbol",
+			"status",
+		},
+		nil,
+	)
+
+	baseBalanceUSD = prometheus.NewDesc(
+		baseBalanceUSDMetricName,
+		"Returns the USD value of the wallet balance on Base blockchain",
+		[]string{
+			"account",
+			"symbol",
+			"status",
+		},
+		nil,
+	)
 )
 
 type BaseCollector struct {
-	Cfg config.Config
+	Cfg    config.Config
+	Prices *PriceFeed
 }
 
 func (b BaseCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- baseBalance
+	if b.Prices != nil {
+		ch <- baseBalanceUSD
+	}
 }
 
 func (b BaseCollector) Collect(ch chan<- prometheus.Metric) {
@@ -69,5 +87,9 @@ func (b BaseCollector) Collect(ch chan<- prometheus.Metric) {
 				status,
 			}...,
 		)
+
+		if err == nil {
+			b.Prices.collectUSD(ctx, ch, baseBalanceUSD, "ETH", balance, addr, "ETH", successStatus)
+		}
 	}
 }
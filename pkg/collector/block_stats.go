@@ -0,0 +1,194 @@
+package collector
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+)
+
+const (
+	blockIntervalMetricName     = "cosmos_chain_window_block_intervals"
+	blockTimeQuantileMetricName = "cosmos_chain_block_time_quantile_seconds"
+	blockTimeMaxMetricName      = "cosmos_chain_block_time_max_seconds"
+	blockTxsMetricName          = "cosmos_chain_block_txs"
+	blockSizeMetricName         = "cosmos_chain_block_size_bytes"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	blockIntervalBuckets = []float64{0.5, 1, 2, 3, 4, 5, 6, 8, 10, 15, 30, 60}
+	blockTimeQuantiles   = []float64{0.5, 0.95}
+
+	// blockInterval is exported as gauges rather than a histogram, as the
+	// counts of a sliding window go down and would break rate().
+	blockInterval = prometheus.NewDesc(
+		blockIntervalMetricName,
+		"Returns the number of intervals between consecutive blocks in the recent window of at most le seconds.",
+		[]string{
+			"chain_id",
+			"le",
+		},
+		nil,
+	)
+
+	blockTimeQuantile = prometheus.NewDesc(
+		blockTimeQuantileMetricName,
+		"Returns block time quantiles in seconds over the recent window.",
+		[]string{
+			"chain_id",
+			"quantile",
+		},
+		nil,
+	)
+
+	blockTimeMax = prometheus.NewDesc(
+		blockTimeMaxMetricName,
+		"Returns the longest block time in seconds in the recent window.",
+		[]string{
+			"chain_id",
+		},
+		nil,
+	)
+
+	blockTxs = prometheus.NewDesc(
+		blockTxsMetricName,
+		"Returns the number of transactions per block over the recent window by statistic (avg, p95, max).",
+		[]string{
+			"chain_id",
+			"stat",
+		},
+		nil,
+	)
+
+	blockSize = prometheus.NewDesc(
+		blockSizeMetricName,
+		"Returns the block size in bytes over the recent window by statistic (avg, p95, max).",
+		[]string{
+			"chain_id",
+			"stat",
+		},
+		nil,
+	)
+)
+
+func describeBlockStats(ch chan<- *prometheus.Desc) {
+	ch <- blockInterval
+	ch <- blockTimeQuantile
+	ch <- blockTimeMax
+	ch <- blockTxs
+	ch <- blockSize
+}
+
+// collectBlockStats exports the block time distribution and the transaction
+// count and size statistics of a window of blocks.
+func collectBlockStats(ch chan<- prometheus.Metric, chainID string, blocks []grpc.BlockStats) {
+	intervals := grpc.BlockIntervals(blocks)
+	if len(intervals) > 0 {
+		sort.Float64s(intervals)
+
+		for _, bound := range blockIntervalBuckets {
+			count := sort.Search(len(intervals), func(i int) bool { return intervals[i] > bound })
+
+			ch <- prometheus.MustNewConstMetric(
+				blockInterval,
+				prometheus.GaugeValue,
+				float64(count),
+				[]string{chainID, strconv.FormatFloat(bound, 'f', -1, 64)}...,
+			)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			blockInterval,
+			prometheus.GaugeValue,
+			float64(len(intervals)),
+			[]string{chainID, "+Inf"}...,
+		)
+
+		for _, q := range blockTimeQuantiles {
+			ch <- prometheus.MustNewConstMetric(
+				blockTimeQuantile,
+				prometheus.GaugeValue,
+				quantile(intervals, q),
+				[]string{chainID, formatQuantile(q)}...,
+			)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			blockTimeMax,
+			prometheus.GaugeValue,
+			intervals[len(intervals)-1],
+			chainID,
+		)
+	}
+
+	if len(blocks) == 0 {
+		return
+	}
+
+	txCounts := make([]float64, 0, len(blocks))
+	sizes := make([]float64, 0, len(blocks))
+	for _, block := range blocks {
+		txCounts = append(txCounts, float64(block.TxCount))
+		sizes = append(sizes, float64(block.Size))
+	}
+
+	collectWindowStats(ch, blockTxs, chainID, txCounts)
+	collectWindowStats(ch, blockSize, chainID, sizes)
+}
+
+// collectWindowStats exports the avg, p95 and max of values. Labels are added
+// after the stat label.
+func collectWindowStats(
+	ch chan<- prometheus.Metric,
+	desc *prometheus.Desc,
+	chainID string,
+	values []float64,
+	labels ...string,
+) {
+	sort.Float64s(values)
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	stats := []struct {
+		name  string
+		value float64
+	}{
+		{"avg", sum / float64(len(values))},
+		{"p95", quantile(values, 0.95)},
+		{"max", values[len(values)-1]},
+	}
+
+	for _, stat := range stats {
+		ch <- prometheus.MustNewConstMetric(
+			desc,
+			prometheus.GaugeValue,
+			stat.value,
+			append([]string{chainID, stat.name}, labels...)...,
+		)
+	}
+}
+
+// quantile returns the q-quantile of sorted values using the nearest-rank
+// method.
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	rank = max(rank, 0)
+	rank = min(rank, len(sorted)-1)
+
+	return sorted[rank]
+}
+
+func formatQuantile(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
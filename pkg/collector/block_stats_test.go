@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+)
+
+// TestCollectBlockIntervals tests that the block intervals of the window are
+// exported as gauges counting the intervals of at most le seconds.
+func TestCollectBlockIntervals(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	blocks := []grpc.BlockStats{
+		{Height: 1, Time: start},
+		{Height: 2, Time: start.Add(1 * time.Second)},
+		{Height: 3, Time: start.Add(3 * time.Second)},
+		{Height: 4, Time: start.Add(10 * time.Second)},
+		{Height: 5, Time: start.Add(100 * time.Second)},
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collectBlockStats(ch, "warden_8765-1", blocks)
+		close(ch)
+	}()
+
+	counts := map[string]float64{}
+	for m := range ch {
+		if m.Desc() != blockInterval {
+			continue
+		}
+
+		metric := &dto.Metric{}
+		if err := m.Write(metric); err != nil {
+			t.Fatalf("error writing metric: %s", err)
+		}
+		if metric.GetGauge() == nil {
+			t.Fatal("block intervals are not exported as gauges")
+		}
+		counts[metricLabels(metric)["le"]] = metric.GetGauge().GetValue()
+	}
+
+	expected := map[string]float64{"0.5": 0, "1": 1, "2": 2, "8": 3, "60": 3, "+Inf": 4}
+	for le, want := range expected {
+		if counts[le] != want {
+			t.Errorf("intervals of at most %s seconds = %v, want %v", le, counts[le], want)
+		}
+	}
+
+	if len(counts) != len(blockIntervalBuckets)+1 {
+		t.Errorf("got %d le series, want %d", len(counts), len(blockIntervalBuckets)+1)
+	}
+}
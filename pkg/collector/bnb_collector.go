@@ -14,27 +14,45 @@ import (
 )
 
 const (
-	bnbBalanceMetricName = "bnb_wallet_balance"
+	bnbBalanceMetricName    = "bnb_wallet_balance"
+	bnbBalanceUSDMetricName = "bnb_wallet_balance_usd"
 )
 
 //nolint:gochecknoglobals // this is needed as it's used in multiple places
-var bnbBalance = prometheus.NewDesc(
-	bnbBalanceMetricName,
-	"Returns the wallet balance on BNB blockchain",
-	[]string{
-		"account",
-		"symbol",
-		"status",
-	},
-	nil,
+var (
+	bnbBalance = prometheus.NewDesc(
+		bnbBalanceMetricName,
+		"Returns the wallet balance on BNB blockchain",
+		[]string{
+			"account",
+			"symbol",
+			"status",
+		},
+		nil,
+	)
+
+	bnbBalanceUSD = prometheus.NewDesc(
+		bnbBalanceUSDMetricName,
+		"Returns the USD value of the wallet balance on BNB blockchain",
+		[]string{
+			"account",
+			"symbol",
+			"status",
+		},
+		nil,
+	)
 )
 
 type BnbCollector struct {
-	Cfg config.Config
+	Cfg    config.Config
+	Prices *PriceFeed
 }
 
 func (bn BnbCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- bnbBalance
+	if bn.Prices != nil {
+		ch <- bnbBalanceUSD
+	}
 }
 
 func (bn BnbCollector) Collect(ch chan<- prometheus.Metric) {
@@ -69,5 +87,9 @@ func (bn BnbCollector) Collect(ch chan<- prometheus.Metric) {
 				status,
 			}...,
 		)
+
+		if err == nil {
+			bn.Prices.collectUSD(ctx, ch, bnbBalanceUSD, "BNB", balance, addr, "BNB", successStatus)
+		}
 	}
 }
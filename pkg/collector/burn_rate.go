@@ -0,0 +1,148 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type balanceSample struct {
+	at    time.Time
+	value float64
+}
+
+// BalanceHistory keeps a rolling history of prepaid balances per target so
+// collectors can derive a burn rate and an estimated time until depletion.
+// It is shared between scrapes, so collectors hold it by pointer.
+type BalanceHistory struct {
+	mu        sync.Mutex
+	windows   []time.Duration
+	retention time.Duration
+	samples   map[string][]balanceSample
+}
+
+func NewBalanceHistory(windows []time.Duration) *BalanceHistory {
+	var retention time.Duration
+	for _, w := range windows {
+		retention = max(retention, w)
+	}
+
+	return &BalanceHistory{
+		windows:   windows,
+		retention: retention,
+		samples:   make(map[string][]balanceSample),
+	}
+}
+
+func (h *BalanceHistory) observe(target string, value float64, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := append(h.samples[target], balanceSample{at: now, value: value})
+
+	cutoff := now.Add(-h.retention)
+	first := 0
+	for first < len(samples)-1 && !samples[first+1].at.After(cutoff) {
+		first++
+	}
+
+	h.samples[target] = samples[first:]
+}
+
+// burnRate returns the amount spent per hour over window. Balance increases
+// between samples are treated as top-ups and ignored, so a top-up does not
+// produce a negative burn rate. The second return value is false until at
+// least two samples are available.
+func (h *BalanceHistory) burnRate(target string, window time.Duration, now time.Time) (float64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.samples[target]
+	cutoff := now.Add(-window)
+
+	first := 0
+	for first < len(samples) && samples[first].at.Before(cutoff) {
+		first++
+	}
+	// Keep the last sample before the window so the window is fully covered.
+	first = max(first-1, 0)
+	samples = samples[first:]
+
+	if len(samples) < 2 {
+		return 0, false
+	}
+
+	var spent float64
+	for i := 1; i < len(samples); i++ {
+		if diff := samples[i-1].value - samples[i].value; diff > 0 {
+			spent += diff
+		}
+	}
+
+	elapsed := samples[len(samples)-1].at.Sub(samples[0].at).Hours()
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	return spent / elapsed, true
+}
+
+// collect records balance for target and emits the burn rate and depletion
+// estimate for every configured window. labels are the target labels of both
+// descriptors, the window label is appended last. A nil history is a no-op.
+func (h *BalanceHistory) collect(
+	ch chan<- prometheus.Metric,
+	rateDesc, depletionDesc *prometheus.Desc,
+	target string,
+	balance float64,
+	labels ...string,
+) {
+	if h == nil {
+		return
+	}
+
+	now := time.Now()
+	h.observe(target, balance, now)
+
+	for _, window := range h.windows {
+		rate, ok := h.burnRate(target, window, now)
+		if !ok {
+			continue
+		}
+
+		depletion := math.Inf(1)
+		if rate > 0 {
+			depletion = max(balance, 0) / rate * time.Hour.Seconds()
+		}
+
+		windowLabels := append(append([]string{}, labels...), formatWindow(window))
+
+		ch <- prometheus.MustNewConstMetric(
+			rateDesc,
+			prometheus.GaugeValue,
+			rate,
+			windowLabels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			depletionDesc,
+			prometheus.GaugeValue,
+			depletion,
+			windowLabels...,
+		)
+	}
+}
+
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBurnRateIgnoresTopUps tests that balance increases are not counted as
+// negative spend.
+func TestBurnRateIgnoresTopUps(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewBalanceHistory([]time.Duration{2 * time.Hour})
+
+	h.observe("acc", 100, start)
+	h.observe("acc", 90, start.Add(30*time.Minute))
+	h.observe("acc", 200, start.Add(time.Hour)) // top-up
+	h.observe("acc", 190, start.Add(2*time.Hour))
+
+	rate, ok := h.burnRate("acc", 2*time.Hour, start.Add(2*time.Hour))
+	if !ok {
+		t.Fatal("expected burn rate to be available")
+	}
+
+	if rate != 10 {
+		t.Errorf("burnRate() = %v, want 10", rate)
+	}
+}
+
+// TestBurnRateNeedsTwoSamples tests that no rate is reported from a single sample.
+func TestBurnRateNeedsTwoSamples(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewBalanceHistory([]time.Duration{time.Hour})
+
+	h.observe("acc", 100, now)
+
+	if _, ok := h.burnRate("acc", time.Hour, now); ok {
+		t.Error("expected no burn rate from a single sample")
+	}
+}
+
+// TestBalanceHistoryRetention tests that samples older than the largest window
+// are dropped, except the one anchoring the start of the window.
+func TestBalanceHistoryRetention(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewBalanceHistory([]time.Duration{time.Hour})
+
+	for i := range 5 {
+		h.observe("acc", float64(100-i), start.Add(time.Duration(i)*time.Hour))
+	}
+
+	if got := len(h.samples["acc"]); got != 2 {
+		t.Errorf("len(samples) = %d, want 2", got)
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	tests := []struct {
+		window   time.Duration
+		expected string
+	}{
+		{time.Hour, "1h"},
+		{24 * time.Hour, "24h"},
+		{30 * time.Minute, "30m"},
+		{90 * time.Second, "1m30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := formatWindow(tt.window); got != tt.expected {
+				t.Errorf("formatWindow(%v) = %s, want %s", tt.window, got, tt.expected)
+			}
+		})
+	}
+}
@@ -18,7 +18,7 @@ const (
 	coinGeckoMonthlyCallCreditMetricName = "coingecko_monthly_call_credit"
 	coinGeckoRemainingCallsMetricName    = "coingecko_current_remaining_monthly_calls"
 	coinGeckoTotalMonthlyCallsMetricName = "coingecko_current_total_monthly_calls"
-	coinGeckoAPIURL                      = "https://pro-api.coingecko.com/api/v3"
+	coinGeckoPriceUSDMetricName          = "coingecko_price_usd"
 )
 
 type CoinGeckoUsageResponse struct {
@@ -35,6 +35,7 @@ var (
 		coinGeckoRateLimitMetricName,
 		"Returns CoinGecko API Key rate limit per minute",
 		[]string{
+			"account",
 			"plan",
 			"status",
 		},
@@ -45,6 +46,7 @@ var (
 		coinGeckoMonthlyCallCreditMetricName,
 		"Returns CoinGecko API Key monthly call credit",
 		[]string{
+			"account",
 			"plan",
 			"status",
 		},
@@ -55,6 +57,7 @@ var (
 		coinGeckoRemainingCallsMetricName,
 		"Returns CoinGecko API Key remaining monthly calls",
 		[]string{
+			"account",
 			"plan",
 			"status",
 		},
@@ -65,15 +68,27 @@ var (
 		coinGeckoTotalMonthlyCallsMetricName,
 		"Returns CoinGecko API Key current total monthly calls",
 		[]string{
+			"account",
 			"plan",
 			"status",
 		},
 		nil,
 	)
+
+	coinGeckoPriceUSD = prometheus.NewDesc(
+		coinGeckoPriceUSDMetricName,
+		"Returns CoinGecko USD price of coin",
+		[]string{
+			"coin_id",
+			"status",
+		},
+		nil,
+	)
 )
 
 type CoinGeckoCollector struct {
-	Cfg config.Config
+	Cfg    config.Config
+	Prices *PriceFeed
 }
 
 func (c CoinGeckoCollector) Describe(ch chan<- *prometheus.Desc) {
@@ -81,19 +96,58 @@ func (c CoinGeckoCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- coinGeckoMonthlyCallCredit
 	ch <- coinGeckoRemainingCalls
 	ch <- coinGeckoTotalMonthlyCalls
+	if c.Prices != nil {
+		ch <- coinGeckoPriceUSD
+	}
 }
 
 func (c CoinGeckoCollector) Collect(ch chan<- prometheus.Metric) {
-	var err error
 	ctx, cancel := context.WithTimeout(
 		context.Background(),
 		time.Duration(c.Cfg.Timeout)*time.Second,
 	)
 	defer cancel()
 
+	for _, account := range config.ParseAPIAccounts(c.Cfg.CoinGeckoAPIKey) {
+		c.collectAccount(ctx, ch, account)
+	}
+
+	if c.Prices != nil {
+		c.collectPrices(ctx, ch)
+	}
+}
+
+func (c CoinGeckoCollector) collectPrices(ctx context.Context, ch chan<- prometheus.Metric) {
+	status := successStatus
+
+	prices, err := c.Prices.Prices(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error collecting CoinGecko prices %s", err))
+		status = errorStatus
+		prices = nil
+	}
+
+	for _, coinID := range c.Prices.coinIDs() {
+		ch <- prometheus.MustNewConstMetric(
+			coinGeckoPriceUSD,
+			prometheus.GaugeValue,
+			prices[coinID],
+			[]string{
+				coinID,
+				status,
+			}...,
+		)
+	}
+}
+
+func (c CoinGeckoCollector) collectAccount(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+) {
 	status := successStatus
 
-	response, err := c.coinGeckoCollectUsage(ctx)
+	response, err := c.coinGeckoCollectUsage(ctx, account.Key)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting CoinGecko usage %s", err))
 		status = errorStatus
@@ -105,6 +159,7 @@ func (c CoinGeckoCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		response.RateLimitRequestPerMinute,
 		[]string{
+			account.Name,
 			response.Plan,
 			status,
 		}...,
@@ -115,6 +170,7 @@ func (c CoinGeckoCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		response.MonthlyCallCredit,
 		[]string{
+			account.Name,
 			response.Plan,
 			status,
 		}...,
@@ -125,6 +181,7 @@ func (c CoinGeckoCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		response.CurrentRemainingMonthlyCalls,
 		[]string{
+			account.Name,
 			response.Plan,
 			status,
 		}...,
@@ -135,6 +192,7 @@ func (c CoinGeckoCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		response.CurrentTotalMonthlyCalls,
 		[]string{
+			account.Name,
 			response.Plan,
 			status,
 		}...,
@@ -143,8 +201,9 @@ func (c CoinGeckoCollector) Collect(ch chan<- prometheus.Metric) {
 
 func (c CoinGeckoCollector) coinGeckoCollectUsage(
 	ctx context.Context,
+	apiKey string,
 ) (CoinGeckoUsageResponse, error) {
-	url := fmt.Sprintf("%s/key", coinGeckoAPIURL)
+	url := fmt.Sprintf("%s/key", c.Cfg.CoinGeckoAPIURL)
 
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -156,7 +215,7 @@ func (c CoinGeckoCollector) coinGeckoCollectUsage(
 		return CoinGeckoUsageResponse{}, fmt.Errorf("error creating request: %w", err)
 	}
 
-	req.Header.Add("x-cg-pro-api-key", c.Cfg.CoinGeckoAPIKey)
+	req.Header.Add("x-cg-pro-api-key", apiKey)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -0,0 +1,404 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	http "github.com/warden-protocol/warden-exporter/pkg/http"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	cometBFTNodeInfoMetricName        = "cometbft_node_info"
+	cometBFTCatchingUpMetricName      = "cometbft_catching_up"
+	cometBFTLatestHeightMetricName    = "cometbft_latest_block_height"
+	cometBFTPeersMetricName           = "cometbft_peers"
+	cometBFTMempoolTxsMetricName      = "cometbft_mempool_txs"
+	cometBFTMempoolBytesMetricName    = "cometbft_mempool_bytes"
+	cometBFTConsensusHeightMetricName = "cometbft_consensus_height"
+	cometBFTConsensusRoundMetricName  = "cometbft_consensus_round"
+	cometBFTConsensusStepMetricName   = "cometbft_consensus_step"
+)
+
+// CometBFTResponse is the JSON-RPC envelope of every CometBFT RPC response.
+type CometBFTResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+type CometBFTStatus struct {
+	NodeInfo struct {
+		Network string `json:"network"`
+		Version string `json:"version"`
+		Moniker string `json:"moniker"`
+	} `json:"node_info"`
+	SyncInfo struct {
+		LatestBlockHeight string `json:"latest_block_height"`
+		LatestBlockTime   string `json:"latest_block_time"`
+		CatchingUp        bool   `json:"catching_up"`
+	} `json:"sync_info"`
+}
+
+type CometBFTNetInfo struct {
+	NPeers string `json:"n_peers"`
+	Peers  []struct {
+		IsOutbound bool `json:"is_outbound"`
+	} `json:"peers"`
+}
+
+type CometBFTUnconfirmedTxs struct {
+	NTxs       string `json:"n_txs"`
+	Total      string `json:"total"`
+	TotalBytes string `json:"total_bytes"`
+}
+
+type CometBFTConsensusState struct {
+	RoundState struct {
+		Height string `json:"height"`
+		Round  int32  `json:"round"`
+		Step   int32  `json:"step"`
+	} `json:"round_state"`
+}
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	cometBFTNodeInfo = prometheus.NewDesc(
+		cometBFTNodeInfoMetricName,
+		"Returns 1 with the network, moniker and version of the CometBFT node",
+		[]string{
+			"chain_id",
+			"network",
+			"moniker",
+			"version",
+		},
+		nil,
+	)
+
+	cometBFTCatchingUp = prometheus.NewDesc(
+		cometBFTCatchingUpMetricName,
+		"Returns 1 if the CometBFT node is catching up, 0 otherwise",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	cometBFTLatestHeight = prometheus.NewDesc(
+		cometBFTLatestHeightMetricName,
+		"Returns the latest block height of the CometBFT node",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	cometBFTPeers = prometheus.NewDesc(
+		cometBFTPeersMetricName,
+		"Returns the number of peers of the CometBFT node by direction",
+		[]string{
+			"chain_id",
+			"direction",
+			"status",
+		},
+		nil,
+	)
+
+	cometBFTMempoolTxs = prometheus.NewDesc(
+		cometBFTMempoolTxsMetricName,
+		"Returns the number of unconfirmed transactions in the CometBFT mempool",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	cometBFTMempoolBytes = prometheus.NewDesc(
+		cometBFTMempoolBytesMetricName,
+		"Returns the total size in bytes of the unconfirmed transactions in the CometBFT mempool",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	cometBFTConsensusHeight = prometheus.NewDesc(
+		cometBFTConsensusHeightMetricName,
+		"Returns the height the CometBFT consensus is working on",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	cometBFTConsensusRound = prometheus.NewDesc(
+		cometBFTConsensusRoundMetricName,
+		"Returns the current CometBFT consensus round of the current height",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	cometBFTConsensusStep = prometheus.NewDesc(
+		cometBFTConsensusStepMetricName,
+		"Returns the current CometBFT consensus step (1 NewHeight, 2 NewRound, 3 Propose, 4 Prevote, "+
+			"5 PrevoteWait, 6 Precommit, 7 PrecommitWait, 8 Commit)",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+)
+
+type CometBFTCollector struct {
+	Cfg config.Config
+}
+
+func (c CometBFTCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cometBFTNodeInfo
+	ch <- cometBFTCatchingUp
+	ch <- cometBFTLatestHeight
+	ch <- cometBFTPeers
+	ch <- cometBFTMempoolTxs
+	ch <- cometBFTMempoolBytes
+	ch <- cometBFTConsensusHeight
+	ch <- cometBFTConsensusRound
+	ch <- cometBFTConsensusStep
+}
+
+func (c CometBFTCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(c.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	var errors []string
+
+	errors = c.collectStatusMetrics(ctx, ch, errors)
+	errors = c.collectNetInfoMetrics(ctx, ch, errors)
+	errors = c.collectMempoolMetrics(ctx, ch, errors)
+	errors = c.collectConsensusMetrics(ctx, ch, errors)
+
+	if len(errors) > 0 {
+		log.Info(fmt.Sprintf("CometBFT metrics collection completed with errors: %v", errors))
+	} else {
+		log.Info("CometBFT metrics collection completed successfully")
+	}
+}
+
+func (c CometBFTCollector) collectStatusMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	status := successStatus
+
+	var resp CometBFTStatus
+	if err := cometBFTGet(ctx, c.Cfg, "/status", &resp); err != nil {
+		log.Error(fmt.Sprintf("error collecting CometBFT status: %s", err))
+		errors = append(errors, "status")
+		status = errorStatus
+		resp = CometBFTStatus{}
+	} else {
+		ch <- prometheus.MustNewConstMetric(
+			cometBFTNodeInfo,
+			prometheus.GaugeValue,
+			1,
+			[]string{c.Cfg.ChainID, resp.NodeInfo.Network, resp.NodeInfo.Moniker, resp.NodeInfo.Version}...,
+		)
+	}
+
+	catchingUp := 0.0
+	if resp.SyncInfo.CatchingUp {
+		catchingUp = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTCatchingUp,
+		prometheus.GaugeValue,
+		catchingUp,
+		[]string{c.Cfg.ChainID, status}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTLatestHeight,
+		prometheus.GaugeValue,
+		parseCometBFTNumber(resp.SyncInfo.LatestBlockHeight),
+		[]string{c.Cfg.ChainID, status}...,
+	)
+
+	return errors
+}
+
+func (c CometBFTCollector) collectNetInfoMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	status := successStatus
+
+	var resp CometBFTNetInfo
+	if err := cometBFTGet(ctx, c.Cfg, "/net_info", &resp); err != nil {
+		log.Error(fmt.Sprintf("error collecting CometBFT net info: %s", err))
+		errors = append(errors, "net info")
+		status = errorStatus
+		resp = CometBFTNetInfo{}
+	}
+
+	var inbound, outbound float64
+	for _, peer := range resp.Peers {
+		if peer.IsOutbound {
+			outbound++
+		} else {
+			inbound++
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTPeers,
+		prometheus.GaugeValue,
+		inbound,
+		[]string{c.Cfg.ChainID, "inbound", status}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTPeers,
+		prometheus.GaugeValue,
+		outbound,
+		[]string{c.Cfg.ChainID, "outbound", status}...,
+	)
+
+	return errors
+}
+
+func (c CometBFTCollector) collectMempoolMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	status := successStatus
+
+	var resp CometBFTUnconfirmedTxs
+	if err := cometBFTGet(ctx, c.Cfg, "/num_unconfirmed_txs", &resp); err != nil {
+		log.Error(fmt.Sprintf("error collecting CometBFT mempool: %s", err))
+		errors = append(errors, "mempool")
+		status = errorStatus
+		resp = CometBFTUnconfirmedTxs{}
+	}
+
+	// total is the mempool size, n_txs only counts the transactions returned
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTMempoolTxs,
+		prometheus.GaugeValue,
+		parseCometBFTNumber(resp.Total),
+		[]string{c.Cfg.ChainID, status}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTMempoolBytes,
+		prometheus.GaugeValue,
+		parseCometBFTNumber(resp.TotalBytes),
+		[]string{c.Cfg.ChainID, status}...,
+	)
+
+	return errors
+}
+
+func (c CometBFTCollector) collectConsensusMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	status := successStatus
+
+	var resp CometBFTConsensusState
+	if err := cometBFTGet(ctx, c.Cfg, "/dump_consensus_state", &resp); err != nil {
+		log.Error(fmt.Sprintf("error collecting CometBFT consensus state: %s", err))
+		errors = append(errors, "consensus state")
+		status = errorStatus
+		resp = CometBFTConsensusState{}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTConsensusHeight,
+		prometheus.GaugeValue,
+		parseCometBFTNumber(resp.RoundState.Height),
+		[]string{c.Cfg.ChainID, status}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTConsensusRound,
+		prometheus.GaugeValue,
+		float64(resp.RoundState.Round),
+		[]string{c.Cfg.ChainID, status}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		cometBFTConsensusStep,
+		prometheus.GaugeValue,
+		float64(resp.RoundState.Step),
+		[]string{c.Cfg.ChainID, status}...,
+	)
+
+	return errors
+}
+
+// cometBFTGet calls a CometBFT RPC endpoint and decodes its result into out.
+func cometBFTGet(ctx context.Context, cfg config.Config, path string, out any) error {
+	reqURL := strings.TrimSuffix(cfg.CometBFTRPCURL, "/") + path
+
+	data, err := http.GetRequestWithHeaders(ctx, reqURL, nil, cfg.HTTPTimeout)
+	if err != nil {
+		return err
+	}
+
+	var resp CometBFTResponse
+	if err = json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+
+	if resp.Error != nil {
+		return fmt.Errorf("RPC error: %s %s", resp.Error.Message, resp.Error.Data)
+	}
+
+	if err = json.Unmarshal(resp.Result, out); err != nil {
+		return fmt.Errorf("error decoding result: %w", err)
+	}
+
+	return nil
+}
+
+// parseCometBFTNumber parses the string encoded integers of the CometBFT RPC,
+// returning 0 for empty or invalid values.
+func parseCometBFTNumber(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		log.Error(fmt.Sprintf("error parsing CometBFT number '%s': %s", s, err))
+		return 0
+	}
+
+	return v
+}
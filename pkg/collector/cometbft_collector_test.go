@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+// newCometBFTServer serves the CometBFT RPC fixtures from testdata.
+func newCometBFTServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture := "cometbft_" + strings.TrimPrefix(r.URL.Path, "/") + ".json"
+
+		data, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+func collectGauges(t *testing.T, c prometheus.Collector) map[*prometheus.Desc][]*dto.Metric {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	metrics := map[*prometheus.Desc][]*dto.Metric{}
+	for m := range ch {
+		metric := &dto.Metric{}
+		if err := m.Write(metric); err != nil {
+			t.Fatalf("error writing metric: %s", err)
+		}
+		metrics[m.Desc()] = append(metrics[m.Desc()], metric)
+	}
+
+	return metrics
+}
+
+// TestCometBFTCollect tests that status, peers, mempool and consensus state
+// are read from a CometBFT RPC endpoint.
+func TestCometBFTCollect(t *testing.T) {
+	server := newCometBFTServer(t)
+	defer server.Close()
+
+	c := CometBFTCollector{
+		Cfg: config.Config{
+			ChainID:        "warden_8765-1",
+			CometBFTRPCURL: server.URL + "/",
+			HTTPTimeout:    5,
+			Timeout:        5,
+		},
+	}
+
+	metrics := collectGauges(t, c)
+
+	expected := map[*prometheus.Desc]float64{
+		cometBFTCatchingUp:      1,
+		cometBFTLatestHeight:    4521980,
+		cometBFTMempoolTxs:      12,
+		cometBFTMempoolBytes:    4096,
+		cometBFTConsensusHeight: 4521981,
+		cometBFTConsensusRound:  2,
+		cometBFTConsensusStep:   4,
+	}
+
+	for desc, want := range expected {
+		got := metrics[desc]
+		if len(got) != 1 || got[0].GetGauge().GetValue() != want {
+			t.Errorf("%s = %v, want %v", desc, got, want)
+		}
+	}
+
+	peers := map[string]float64{}
+	for _, metric := range metrics[cometBFTPeers] {
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "direction" {
+				peers[label.GetValue()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	if peers["inbound"] != 2 || peers["outbound"] != 1 {
+		t.Errorf("peers = %v, want inbound 2 and outbound 1", peers)
+	}
+}
+
+// TestCometBFTCollectUnavailable tests that an unreachable endpoint emits
+// error series and no node info.
+func TestCometBFTCollectUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	c := CometBFTCollector{
+		Cfg: config.Config{
+			CometBFTRPCURL: server.URL,
+			HTTPTimeout:    5,
+			Timeout:        5,
+		},
+	}
+
+	metrics := collectGauges(t, c)
+
+	if len(metrics[cometBFTNodeInfo]) != 0 {
+		t.Error("expected no node info for an unreachable endpoint")
+	}
+
+	for _, label := range metrics[cometBFTLatestHeight][0].GetLabel() {
+		if label.GetName() == statusLabel && label.GetValue() != errorStatus {
+			t.Errorf("status = %s, want %s", label.GetValue(), errorStatus)
+		}
+	}
+}
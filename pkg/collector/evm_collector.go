@@ -0,0 +1,336 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	evmBaseFeeMetricName           = "evm_base_fee_wei"
+	evmGasPriceMetricName          = "evm_gas_price_wei"
+	evmBlockHeightMetricName       = "evm_block_height"
+	evmBlockGasUsedMetricName      = "evm_block_gas_used"
+	evmBlockGasLimitMetricName     = "evm_block_gas_limit"
+	evmBlockGasUtilizationName     = "evm_block_gas_utilization_ratio"
+	evmBlockTxsMetricName          = "evm_block_txs"
+	evmChainIDConsistentMetricName = "evm_chain_id_consistent"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	// cosmosEVMChainID matches Cosmos EVM chain ids such as warden_8765-1,
+	// whose number after the underscore is the EIP-155 chain id
+	cosmosEVMChainID = regexp.MustCompile(`_(\d+)-\d+$`)
+
+	evmBaseFee = prometheus.NewDesc(
+		evmBaseFeeMetricName,
+		"Returns the base fee per gas in wei of the latest EVM block, from the baseFeePerGas of the JSON-RPC block",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	evmGasPrice = prometheus.NewDesc(
+		evmGasPriceMetricName,
+		"Returns the gas price in wei suggested by the EVM JSON-RPC",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	evmBlockHeight = prometheus.NewDesc(
+		evmBlockHeightMetricName,
+		"Returns the height of the latest EVM block",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	evmBlockGasUsed = prometheus.NewDesc(
+		evmBlockGasUsedMetricName,
+		"Returns the gas used by the latest EVM block",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	evmBlockGasLimit = prometheus.NewDesc(
+		evmBlockGasLimitMetricName,
+		"Returns the gas limit of the latest EVM block",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	evmBlockGasUtilization = prometheus.NewDesc(
+		evmBlockGasUtilizationName,
+		"Returns the share of the gas limit used by the latest EVM block",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	evmBlockTxs = prometheus.NewDesc(
+		evmBlockTxsMetricName,
+		"Returns the number of EVM transactions in the latest EVM block",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	evmChainIDConsistent = prometheus.NewDesc(
+		evmChainIDConsistentMetricName,
+		"Returns 1 when eth_chainId, net_version and the EIP-155 chain id in CHAIN_ID agree, 0 otherwise",
+		[]string{
+			"chain_id",
+			"eth_chain_id",
+			"net_version",
+			"status",
+		},
+		nil,
+	)
+)
+
+// evmBlock is the part of an eth_getBlockByNumber result the collector uses.
+type evmBlock struct {
+	Number        string   `json:"number"`
+	GasUsed       string   `json:"gasUsed"`
+	GasLimit      string   `json:"gasLimit"`
+	BaseFeePerGas string   `json:"baseFeePerGas"`
+	Transactions  []string `json:"transactions"`
+}
+
+type EVMCollector struct {
+	Cfg config.Config
+}
+
+func (e EVMCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- evmBaseFee
+	ch <- evmGasPrice
+	ch <- evmBlockHeight
+	ch <- evmBlockGasUsed
+	ch <- evmBlockGasLimit
+	ch <- evmBlockGasUtilization
+	ch <- evmBlockTxs
+	ch <- evmChainIDConsistent
+}
+
+func (e EVMCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(e.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	var errors []string
+
+	errors = e.collectBlockMetrics(ctx, ch, errors)
+	errors = e.collectGasPriceMetrics(ctx, ch, errors)
+	errors = e.collectChainIDMetrics(ctx, ch, errors)
+
+	if len(errors) > 0 {
+		log.Info(fmt.Sprintf("EVM metrics collection completed with errors: %v", errors))
+	} else {
+		log.Info("EVM metrics collection completed successfully")
+	}
+}
+
+func (e EVMCollector) collectBlockMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	var height, gasUsed, gasLimit, baseFee, utilization, txs float64
+
+	status := successStatus
+	block, err := e.latestBlock(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting latest EVM block: %s", err))
+		errors = append(errors, "block")
+		status = errorStatus
+	} else {
+		height, gasUsed, gasLimit, baseFee = block.height, block.gasUsed, block.gasLimit, block.baseFee
+		txs = float64(block.txs)
+		if gasLimit > 0 {
+			utilization = gasUsed / gasLimit
+		}
+	}
+
+	for desc, value := range map[*prometheus.Desc]float64{
+		evmBlockHeight:         height,
+		evmBlockGasUsed:        gasUsed,
+		evmBlockGasLimit:       gasLimit,
+		evmBlockGasUtilization: utilization,
+		evmBlockTxs:            txs,
+		evmBaseFee:             baseFee,
+	} {
+		ch <- prometheus.MustNewConstMetric(
+			desc,
+			prometheus.GaugeValue,
+			value,
+			[]string{e.Cfg.ChainID, status}...,
+		)
+	}
+
+	return errors
+}
+
+type latestEVMBlock struct {
+	height   float64
+	gasUsed  float64
+	gasLimit float64
+	baseFee  float64
+	txs      int
+}
+
+func (e EVMCollector) latestBlock(ctx context.Context) (latestEVMBlock, error) {
+	var block evmBlock
+	err := callJSONRPC(ctx, e.Cfg.EVMRPCURL, "eth_getBlockByNumber", []any{"latest", false}, e.Cfg.Timeout, &block)
+	if err != nil {
+		return latestEVMBlock{}, err
+	}
+
+	latest := latestEVMBlock{txs: len(block.Transactions)}
+	if latest.height, err = hexQuantityFloat(block.Number); err != nil {
+		return latestEVMBlock{}, err
+	}
+	if latest.gasUsed, err = hexQuantityFloat(block.GasUsed); err != nil {
+		return latestEVMBlock{}, err
+	}
+	if latest.gasLimit, err = hexQuantityFloat(block.GasLimit); err != nil {
+		return latestEVMBlock{}, err
+	}
+
+	// Chains without a fee market have no base fee
+	if block.BaseFeePerGas != "" {
+		if latest.baseFee, err = hexQuantityFloat(block.BaseFeePerGas); err != nil {
+			return latestEVMBlock{}, err
+		}
+	}
+
+	return latest, nil
+}
+
+func (e EVMCollector) collectGasPriceMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	var gasPrice float64
+
+	status := successStatus
+	var result string
+	err := callJSONRPC(ctx, e.Cfg.EVMRPCURL, "eth_gasPrice", []any{}, e.Cfg.Timeout, &result)
+	if err == nil {
+		gasPrice, err = hexQuantityFloat(result)
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting EVM gas price: %s", err))
+		errors = append(errors, "gas price")
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		evmGasPrice,
+		prometheus.GaugeValue,
+		gasPrice,
+		[]string{e.Cfg.ChainID, status}...,
+	)
+
+	return errors
+}
+
+func (e EVMCollector) collectChainIDMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	ethChainID, netVersion := unknownGroup, unknownGroup
+	status := successStatus
+
+	var chainIDHex string
+	err := callJSONRPC(ctx, e.Cfg.EVMRPCURL, "eth_chainId", []any{}, e.Cfg.Timeout, &chainIDHex)
+	if err == nil {
+		var value *big.Int
+		value, err = parseHexQuantity(chainIDHex)
+		if err == nil {
+			ethChainID = value.String()
+		}
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting eth_chainId: %s", err))
+		errors = append(errors, "eth_chainId")
+		status = errorStatus
+	}
+
+	if err = callJSONRPC(ctx, e.Cfg.EVMRPCURL, "net_version", []any{}, e.Cfg.Timeout, &netVersion); err != nil {
+		log.Error(fmt.Sprintf("error getting net_version: %s", err))
+		errors = append(errors, "net_version")
+		status = errorStatus
+		netVersion = unknownGroup
+	}
+
+	consistent := 0.0
+	if status == successStatus && evmChainIDsConsistent(e.Cfg.ChainID, ethChainID, netVersion) {
+		consistent = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		evmChainIDConsistent,
+		prometheus.GaugeValue,
+		consistent,
+		[]string{e.Cfg.ChainID, ethChainID, netVersion, status}...,
+	)
+
+	return errors
+}
+
+// evmChainIDsConsistent reports whether eth_chainId matches net_version and,
+// for Cosmos EVM chain ids such as warden_8765-1, the EIP-155 chain id in
+// CHAIN_ID.
+func evmChainIDsConsistent(chainID, ethChainID, netVersion string) bool {
+	if ethChainID != netVersion {
+		return false
+	}
+
+	match := cosmosEVMChainID.FindStringSubmatch(chainID)
+	if match == nil {
+		return true
+	}
+
+	return match[1] == ethChainID
+}
+
+// hexQuantityFloat parses a hex encoded JSON-RPC quantity into a float64.
+func hexQuantityFloat(quantity string) (float64, error) {
+	value, err := parseHexQuantity(quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	f, _ := new(big.Float).SetInt(value).Float64()
+
+	return f, nil
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+// newEVMServer serves the EVM JSON-RPC methods used by the EVM collector.
+func newEVMServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	results := map[string]string{
+		"eth_getBlockByNumber": `{"number":"0x64","gasUsed":"0x2710","gasLimit":"0x9c40",` +
+			`"baseFeePerGas":"0x3b9aca00","transactions":["0x1","0x2"]}`,
+		"eth_gasPrice": `"0x77359400"`,
+		"eth_chainId":  `"0x223d"`,
+		"net_version":  `"8765"`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("error decoding request: %s", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, results[req.Method])
+	}))
+}
+
+// TestEVMCollect tests that the latest block, gas price and chain id
+// consistency are read from the EVM JSON-RPC.
+func TestEVMCollect(t *testing.T) {
+	server := newEVMServer(t)
+	defer server.Close()
+
+	metrics := collectGauges(t, EVMCollector{Cfg: config.Config{
+		ChainID:   "warden_8765-1",
+		EVMRPCURL: server.URL,
+		Timeout:   5,
+	}})
+
+	want := map[*prometheus.Desc]float64{
+		evmBlockHeight:         100,
+		evmBlockGasUsed:        10000,
+		evmBlockGasLimit:       40000,
+		evmBlockGasUtilization: 0.25,
+		evmBlockTxs:            2,
+		evmBaseFee:             1e9,
+		evmGasPrice:            2e9,
+		evmChainIDConsistent:   1,
+	}
+
+	for desc, value := range want {
+		if len(metrics[desc]) != 1 {
+			t.Fatalf("expected 1 %s metric, got %d", desc, len(metrics[desc]))
+		}
+
+		m := metrics[desc][0]
+		if got := m.GetGauge().GetValue(); got != value {
+			t.Errorf("%s = %v, want %v", desc, got, value)
+		}
+		if status := metricLabels(m)["status"]; status != successStatus {
+			t.Errorf("%s status = %s, want %s", desc, status, successStatus)
+		}
+	}
+}
+
+// TestEVMChainIDsConsistent tests that eth_chainId is compared to net_version
+// and to the EIP-155 chain id of Cosmos EVM chain ids.
+func TestEVMChainIDsConsistent(t *testing.T) {
+	tests := []struct {
+		chainID    string
+		ethChainID string
+		netVersion string
+		want       bool
+	}{
+		{"warden_8765-1", "8765", "8765", true},
+		{"warden_8765-1", "8765", "1", false},
+		{"warden_1-1", "8765", "8765", false},
+		{"barra_9191-1", "9191", "9191", true},
+		{"chiado", "10010", "10010", true},
+	}
+
+	for _, tt := range tests {
+		if got := evmChainIDsConsistent(tt.chainID, tt.ethChainID, tt.netVersion); got != tt.want {
+			t.Errorf("evmChainIDsConsistent(%s, %s, %s) = %v, want %v",
+				tt.chainID, tt.ethChainID, tt.netVersion, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	http "github.com/warden-protocol/warden-exporter/pkg/http"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	geminiSpendMetricName       = "gemini_monthly_spend"
+	geminiBudgetMetricName      = "gemini_budget_amount"
+	geminiUtilisationMetricName = "gemini_budget_utilisation_ratio"
+)
+
+// GeminiBillingResponse is served by GEMINI_BILLING_URL. Every entry follows
+// the Cloud Billing budget notification format, extended with the project
+// the budget is scoped to, so the endpoint can be backed either by budget
+// notifications or by a query over the BigQuery billing export.
+type GeminiBillingResponse struct {
+	Budgets []struct {
+		ProjectID         string  `json:"projectId"`
+		BudgetDisplayName string  `json:"budgetDisplayName"`
+		CostAmount        float64 `json:"costAmount"`
+		CostIntervalStart string  `json:"costIntervalStart"`
+		BudgetAmount      float64 `json:"budgetAmount"`
+		BudgetAmountType  string  `json:"budgetAmountType"`
+		CurrencyCode      string  `json:"currencyCode"`
+	} `json:"budgets"`
+}
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	geminiSpend = prometheus.NewDesc(
+		geminiSpendMetricName,
+		"Returns Gemini / Vertex AI spend in the current billing month by project and budget",
+		[]string{
+			"project_id",
+			"budget",
+			"currency",
+			"status",
+		},
+		nil,
+	)
+
+	geminiBudget = prometheus.NewDesc(
+		geminiBudgetMetricName,
+		"Returns Gemini / Vertex AI monthly budget amount by project and budget",
+		[]string{
+			"project_id",
+			"budget",
+			"currency",
+			"status",
+		},
+		nil,
+	)
+
+	geminiUtilisation = prometheus.NewDesc(
+		geminiUtilisationMetricName,
+		"Returns Gemini / Vertex AI spend in the current billing month as a ratio of the budget amount",
+		[]string{
+			"project_id",
+			"budget",
+			"status",
+		},
+		nil,
+	)
+)
+
+type GeminiCollector struct {
+	Cfg config.Config
+}
+
+func (g GeminiCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- geminiSpend
+	ch <- geminiBudget
+	ch <- geminiUtilisation
+}
+
+func (g GeminiCollector) Collect(ch chan<- prometheus.Metric) {
+	g.collectFailures(ch)
+}
+
+// collectFailures reports a failed billing request, after which no series
+// are exported.
+func (g GeminiCollector) collectFailures(ch chan<- prometheus.Metric) []string {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(g.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	var errors []string
+
+	errors = g.collectBudgetMetrics(ctx, ch, errors)
+
+	if len(errors) > 0 {
+		log.Info(fmt.Sprintf("Gemini metrics collection completed with errors: %v", errors))
+		return []string{""}
+	}
+
+	log.Info("Gemini metrics collection completed successfully")
+
+	return nil
+}
+
+func (g GeminiCollector) collectBudgetMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	billing, err := g.geminiCollectBilling(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error collecting Gemini billing: %s", err))
+		return append(errors, "billing")
+	}
+
+	for _, budget := range billing.Budgets {
+		ch <- prometheus.MustNewConstMetric(
+			geminiSpend,
+			prometheus.GaugeValue,
+			budget.CostAmount,
+			[]string{budget.ProjectID, budget.BudgetDisplayName, budget.CurrencyCode, successStatus}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			geminiBudget,
+			prometheus.GaugeValue,
+			budget.BudgetAmount,
+			[]string{budget.ProjectID, budget.BudgetDisplayName, budget.CurrencyCode, successStatus}...,
+		)
+
+		if budget.BudgetAmount <= 0 {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			geminiUtilisation,
+			prometheus.GaugeValue,
+			budget.CostAmount/budget.BudgetAmount,
+			[]string{budget.ProjectID, budget.BudgetDisplayName, successStatus}...,
+		)
+	}
+
+	return errors
+}
+
+func (g GeminiCollector) geminiCollectBilling(ctx context.Context) (GeminiBillingResponse, error) {
+	data, err := http.GetRequest(ctx, g.Cfg.GeminiBillingURL, g.Cfg.GeminiBillingToken, g.Cfg.HTTPTimeout)
+	if err != nil {
+		return GeminiBillingResponse{}, err
+	}
+
+	var billingResponse GeminiBillingResponse
+	if err = json.Unmarshal(data, &billingResponse); err != nil {
+		return GeminiBillingResponse{}, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return billingResponse, nil
+}
@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+const testGeminiToken = "gemini-billing-token"
+
+func newGeminiServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testGeminiToken {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", "gemini_billing.json"))
+		if err != nil {
+			t.Errorf("error reading fixture: %s", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+// TestGeminiCollect tests that spend, budget and utilisation are exported
+// per budget, and that utilisation is skipped for budgets without an amount.
+func TestGeminiCollect(t *testing.T) {
+	server := newGeminiServer(t)
+	defer server.Close()
+
+	g := GeminiCollector{
+		Cfg: config.Config{
+			GeminiBillingURL:   server.URL,
+			GeminiBillingToken: testGeminiToken,
+			HTTPTimeout:        5,
+			Timeout:            5,
+		},
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		g.Collect(ch)
+		close(ch)
+	}()
+
+	values := map[string][]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("error writing metric: %s", err)
+		}
+		values[m.Desc().String()] = append(values[m.Desc().String()], metric.GetGauge().GetValue())
+	}
+
+	spend := values[geminiSpend.String()]
+	if len(spend) != 2 || spend[0] != 140.25 || spend[1] != 12.5 {
+		t.Errorf("spend = %v, want [140.25 12.5]", spend)
+	}
+
+	utilisation := values[geminiUtilisation.String()]
+	if len(utilisation) != 1 || utilisation[0] != 0.2805 {
+		t.Errorf("utilisation = %v, want [0.2805]", utilisation)
+	}
+}
+
+// TestGeminiCollectUnauthorized tests that a failing endpoint emits no series.
+func TestGeminiCollectUnauthorized(t *testing.T) {
+	server := newGeminiServer(t)
+	defer server.Close()
+
+	g := GeminiCollector{
+		Cfg: config.Config{
+			GeminiBillingURL: server.URL,
+			HTTPTimeout:      5,
+			Timeout:          5,
+		},
+	}
+
+	if _, err := g.geminiCollectBilling(t.Context()); err == nil {
+		t.Error("expected an error for an unauthorized request")
+	}
+
+	if metrics := collectGauges(t, g); len(metrics) != 0 {
+		t.Errorf("Collect() emitted %d metric families, want none", len(metrics))
+	}
+}
@@ -0,0 +1,463 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	channeltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	ibcClientLatestHeightMetricName       = "ibc_client_latest_height"
+	ibcClientTrustingRemainingMetricName  = "ibc_client_trusting_period_remaining_seconds"
+	ibcClientActiveMetricName             = "ibc_client_active"
+	ibcConnectionStateMetricName          = "ibc_connection_state"
+	ibcChannelStateMetricName             = "ibc_channel_state"
+	ibcChannelPacketCommitmentsMetricName = "ibc_channel_packet_commitments"
+	ibcChannelUnreceivedPacketsMetricName = "ibc_channel_unreceived_packets"
+	ibcChannelUnreceivedAcksMetricName    = "ibc_channel_unreceived_acks"
+	ibcStatePrefix                        = "STATE_"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	ibcClientLatestHeight = prometheus.NewDesc(
+		ibcClientLatestHeightMetricName,
+		"Returns the latest counterparty height the IBC light client was updated to",
+		[]string{
+			"chain_id",
+			"client_id",
+			"counterparty_chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	ibcClientTrustingRemaining = prometheus.NewDesc(
+		ibcClientTrustingRemainingMetricName,
+		"Returns the seconds left until the IBC light client expires if it is not updated",
+		[]string{
+			"chain_id",
+			"client_id",
+			"counterparty_chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	ibcClientActive = prometheus.NewDesc(
+		ibcClientActiveMetricName,
+		"Returns 1 if the IBC light client is active, 0 if it is expired, frozen or unknown",
+		[]string{
+			"chain_id",
+			"client_id",
+			"counterparty_chain_id",
+			"client_status",
+		},
+		nil,
+	)
+
+	ibcConnectionState = prometheus.NewDesc(
+		ibcConnectionStateMetricName,
+		"Returns 1 with the state of the IBC connection",
+		[]string{
+			"chain_id",
+			"connection_id",
+			"client_id",
+			"counterparty_chain_id",
+			"state",
+		},
+		nil,
+	)
+
+	ibcChannelState = prometheus.NewDesc(
+		ibcChannelStateMetricName,
+		"Returns 1 with the state of the IBC channel",
+		[]string{
+			"chain_id",
+			"port_id",
+			"channel_id",
+			"connection_id",
+			"counterparty_chain_id",
+			"state",
+		},
+		nil,
+	)
+
+	ibcChannelPacketCommitments = prometheus.NewDesc(
+		ibcChannelPacketCommitmentsMetricName,
+		"Returns the number of packets sent on the IBC channel that are not acknowledged or timed out yet",
+		[]string{
+			"chain_id",
+			"port_id",
+			"channel_id",
+			"counterparty_chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	ibcChannelUnreceivedPackets = prometheus.NewDesc(
+		ibcChannelUnreceivedPacketsMetricName,
+		"Returns the number of packets sent on the IBC channel that the counterparty chain has not received",
+		[]string{
+			"chain_id",
+			"port_id",
+			"channel_id",
+			"counterparty_chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	ibcChannelUnreceivedAcks = prometheus.NewDesc(
+		ibcChannelUnreceivedAcksMetricName,
+		"Returns the number of acknowledgements written on the IBC channel that the counterparty chain has not received",
+		[]string{
+			"chain_id",
+			"port_id",
+			"channel_id",
+			"counterparty_chain_id",
+			"status",
+		},
+		nil,
+	)
+)
+
+// IBCCollector exports the state of the IBC light clients, connections and
+// channels and the packets pending on every open channel.
+type IBCCollector struct {
+	Cfg config.Config
+	// Counterparties are the gRPC endpoints of counterparty chains by chain
+	// id, used to count unreceived packets and acknowledgements.
+	Counterparties map[string]config.NodeEndpoint
+}
+
+func (i IBCCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ibcClientLatestHeight
+	ch <- ibcClientTrustingRemaining
+	ch <- ibcClientActive
+	ch <- ibcConnectionState
+	ch <- ibcChannelState
+	ch <- ibcChannelPacketCommitments
+	ch <- ibcChannelUnreceivedPackets
+	ch <- ibcChannelUnreceivedAcks
+}
+
+func (i IBCCollector) Collect(ch chan<- prometheus.Metric) {
+	i.collectFailures(ch)
+}
+
+// collectFailures reports failed client, connection or channel queries, after
+// which their series are not exported.
+func (i IBCCollector) collectFailures(ch chan<- prometheus.Metric) []string {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(i.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	client, err := grpc.NewClient(i.Cfg)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting IBC metrics: %s", err))
+		return []string{""}
+	}
+
+	defer func() {
+		if tempErr := client.CloseConn(); tempErr != nil {
+			log.Error(tempErr.Error())
+		}
+	}()
+
+	var errors []string
+
+	// clientChains and connectionChains map clients and connections to the
+	// chain on the other side
+	clientChains := map[string]string{}
+	connectionChains := map[string]string{}
+
+	errors = i.collectClientMetrics(ctx, ch, client, clientChains, errors)
+	errors = i.collectConnectionMetrics(ctx, ch, client, clientChains, connectionChains, errors)
+	errors = i.collectChannelMetrics(ctx, ch, client, connectionChains, errors)
+
+	if len(errors) > 0 {
+		log.Info(fmt.Sprintf("IBC metrics collection completed with errors: %v", errors))
+		return []string{""}
+	}
+
+	log.Info("IBC metrics collection completed successfully")
+
+	return nil
+}
+
+func (i IBCCollector) collectClientMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	clientChains map[string]string,
+	errors []string,
+) []string {
+	clients, err := client.IBCClients(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting IBC clients: %s", err))
+		return append(errors, "clients")
+	}
+
+	for _, c := range clients {
+		clientChains[c.ClientID] = c.ChainID
+
+		ch <- prometheus.MustNewConstMetric(
+			ibcClientLatestHeight,
+			prometheus.GaugeValue,
+			float64(c.LatestHeight),
+			[]string{i.Cfg.ChainID, c.ClientID, c.ChainID, successStatus}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			ibcClientTrustingRemaining,
+			prometheus.GaugeValue,
+			time.Until(c.ExpiresAt()).Seconds(),
+			[]string{i.Cfg.ChainID, c.ClientID, c.ChainID, successStatus}...,
+		)
+
+		active := 0.0
+		if c.Status == string(ibcexported.Active) {
+			active = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			ibcClientActive,
+			prometheus.GaugeValue,
+			active,
+			[]string{i.Cfg.ChainID, c.ClientID, c.ChainID, c.Status}...,
+		)
+	}
+
+	return errors
+}
+
+func (i IBCCollector) collectConnectionMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	clientChains map[string]string,
+	connectionChains map[string]string,
+	errors []string,
+) []string {
+	connections, err := client.IBCConnections(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting IBC connections: %s", err))
+		return append(errors, "connections")
+	}
+
+	for _, connection := range connections {
+		chainID := ibcCounterpartyChain(clientChains, connection.ClientId)
+		connectionChains[connection.Id] = chainID
+
+		ch <- prometheus.MustNewConstMetric(
+			ibcConnectionState,
+			prometheus.GaugeValue,
+			1,
+			[]string{
+				i.Cfg.ChainID,
+				connection.Id,
+				connection.ClientId,
+				chainID,
+				ibcStateLabel(connection.State.String()),
+			}...,
+		)
+	}
+
+	return errors
+}
+
+func (i IBCCollector) collectChannelMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	connectionChains map[string]string,
+	errors []string,
+) []string {
+	channels, err := client.IBCChannels(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting IBC channels: %s", err))
+		return append(errors, "channels")
+	}
+
+	watched := map[string]bool{}
+	for _, channelID := range splitCommaList(i.Cfg.IBCChannels) {
+		watched[channelID] = true
+	}
+
+	counterparties := map[string]grpc.Client{}
+	defer func() {
+		for _, c := range counterparties {
+			if tempErr := c.CloseConn(); tempErr != nil {
+				log.Error(tempErr.Error())
+			}
+		}
+	}()
+
+	for _, channel := range channels {
+		connectionID := ""
+		if len(channel.ConnectionHops) > 0 {
+			connectionID = channel.ConnectionHops[0]
+		}
+		chainID := ibcCounterpartyChain(connectionChains, connectionID)
+
+		ch <- prometheus.MustNewConstMetric(
+			ibcChannelState,
+			prometheus.GaugeValue,
+			1,
+			[]string{
+				i.Cfg.ChainID,
+				channel.PortId,
+				channel.ChannelId,
+				connectionID,
+				chainID,
+				ibcStateLabel(channel.State.String()),
+			}...,
+		)
+
+		if channel.State != channeltypes.OPEN || (len(watched) > 0 && !watched[channel.ChannelId]) {
+			continue
+		}
+
+		counterparty, ok := i.counterpartyClient(counterparties, chainID)
+		if !i.collectPacketMetrics(ctx, ch, client, counterparty, ok, channel, chainID) {
+			errors = append(errors, "packets "+channel.ChannelId)
+		}
+	}
+
+	return errors
+}
+
+// collectPacketMetrics exports the pending packets of an open channel. The
+// unreceived packets and acknowledgements are only exported when the
+// counterparty chain is configured. It returns false on errors.
+func (i IBCCollector) collectPacketMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	counterparty grpc.Client,
+	hasCounterparty bool,
+	channel *channeltypes.IdentifiedChannel,
+	chainID string,
+) bool {
+	labels := func(status string) []string {
+		return []string{i.Cfg.ChainID, channel.PortId, channel.ChannelId, chainID, status}
+	}
+	succeeded := true
+
+	status := successStatus
+	commitments, err := client.IBCPacketCommitments(ctx, channel.PortId, channel.ChannelId)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting packet commitments of channel %s: %s", channel.ChannelId, err))
+		status = errorStatus
+		succeeded = false
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		ibcChannelPacketCommitments,
+		prometheus.GaugeValue,
+		float64(len(commitments)),
+		labels(status)...,
+	)
+
+	if !hasCounterparty {
+		return succeeded
+	}
+
+	cpPort, cpChannel := channel.Counterparty.PortId, channel.Counterparty.ChannelId
+
+	if status == successStatus {
+		var unreceived []uint64
+		unreceived, err = counterparty.IBCUnreceivedPackets(ctx, cpPort, cpChannel, commitments)
+		if err != nil {
+			log.Error(fmt.Sprintf("error getting unreceived packets of channel %s: %s", channel.ChannelId, err))
+			status = errorStatus
+			succeeded = false
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			ibcChannelUnreceivedPackets,
+			prometheus.GaugeValue,
+			float64(len(unreceived)),
+			labels(status)...,
+		)
+	} else {
+		ch <- prometheus.MustNewConstMetric(ibcChannelUnreceivedPackets, prometheus.GaugeValue, 0, labels(errorStatus)...)
+	}
+
+	// Only the packets the counterparty is still waiting on can have an
+	// acknowledgement in flight
+	status = successStatus
+	unreceivedAcks := []uint64{}
+	cpCommitments, err := counterparty.IBCPacketCommitments(ctx, cpPort, cpChannel)
+	if err == nil {
+		var acks []uint64
+		acks, err = client.IBCPacketAcknowledgements(ctx, channel.PortId, channel.ChannelId, cpCommitments)
+		if err == nil {
+			unreceivedAcks, err = counterparty.IBCUnreceivedAcks(ctx, cpPort, cpChannel, acks)
+		}
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting unreceived acknowledgements of channel %s: %s", channel.ChannelId, err))
+		status = errorStatus
+		succeeded = false
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		ibcChannelUnreceivedAcks,
+		prometheus.GaugeValue,
+		float64(len(unreceivedAcks)),
+		labels(status)...,
+	)
+
+	return succeeded
+}
+
+// counterpartyClient returns a client for a configured counterparty chain,
+// connecting on first use. The second return value is false when the chain is
+// not configured or the client cannot be created.
+func (i IBCCollector) counterpartyClient(clients map[string]grpc.Client, chainID string) (grpc.Client, bool) {
+	if c, ok := clients[chainID]; ok {
+		return c, true
+	}
+
+	endpoint, ok := i.Counterparties[chainID]
+	if !ok {
+		return grpc.Client{}, false
+	}
+
+	c, err := grpc.NewClient(i.Cfg.ForNode(endpoint))
+	if err != nil {
+		log.Error(fmt.Sprintf("error connecting to IBC counterparty %s: %s", chainID, err))
+		return grpc.Client{}, false
+	}
+	clients[chainID] = c
+
+	return c, true
+}
+
+func ibcCounterpartyChain(chains map[string]string, id string) string {
+	if chainID, ok := chains[id]; ok && chainID != "" {
+		return chainID
+	}
+
+	return unknownGroup
+}
+
+// ibcStateLabel turns a channel or connection state such as STATE_OPEN into
+// a label value such as open.
+func ibcStateLabel(state string) string {
+	return strings.ToLower(strings.TrimPrefix(state, ibcStatePrefix))
+}
@@ -0,0 +1,219 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	connectiontypes "github.com/cosmos/ibc-go/v8/modules/core/03-connection/types"
+	channeltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	ibctm "github.com/cosmos/ibc-go/v8/modules/light-clients/07-tendermint"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+type ibcClientServer struct {
+	clienttypes.UnimplementedQueryServer
+	timestamp time.Time
+}
+
+func (s *ibcClientServer) ClientStates(
+	_ context.Context,
+	_ *clienttypes.QueryClientStatesRequest,
+) (*clienttypes.QueryClientStatesResponse, error) {
+	clientState := &ibctm.ClientState{
+		ChainId:        "osmosis-1",
+		TrustingPeriod: time.Hour,
+		LatestHeight:   clienttypes.NewHeight(1, 100),
+	}
+
+	return &clienttypes.QueryClientStatesResponse{
+		ClientStates: clienttypes.IdentifiedClientStates{
+			clienttypes.NewIdentifiedClientState("07-tendermint-0", clientState),
+		},
+	}, nil
+}
+
+func (s *ibcClientServer) ClientStatus(
+	_ context.Context,
+	_ *clienttypes.QueryClientStatusRequest,
+) (*clienttypes.QueryClientStatusResponse, error) {
+	return &clienttypes.QueryClientStatusResponse{Status: string(ibcexported.Active)}, nil
+}
+
+func (s *ibcClientServer) ConsensusState(
+	_ context.Context,
+	_ *clienttypes.QueryConsensusStateRequest,
+) (*clienttypes.QueryConsensusStateResponse, error) {
+	consensusState, err := clienttypes.PackConsensusState(&ibctm.ConsensusState{Timestamp: s.timestamp})
+	if err != nil {
+		return nil, err
+	}
+
+	return &clienttypes.QueryConsensusStateResponse{ConsensusState: consensusState}, nil
+}
+
+type ibcConnectionServer struct {
+	connectiontypes.UnimplementedQueryServer
+}
+
+func (*ibcConnectionServer) Connections(
+	_ context.Context,
+	_ *connectiontypes.QueryConnectionsRequest,
+) (*connectiontypes.QueryConnectionsResponse, error) {
+	return &connectiontypes.QueryConnectionsResponse{
+		Connections: []*connectiontypes.IdentifiedConnection{
+			{Id: "connection-0", ClientId: "07-tendermint-0", State: connectiontypes.OPEN},
+		},
+	}, nil
+}
+
+type ibcChannelServer struct {
+	channeltypes.UnimplementedQueryServer
+}
+
+func (*ibcChannelServer) Channels(
+	_ context.Context,
+	_ *channeltypes.QueryChannelsRequest,
+) (*channeltypes.QueryChannelsResponse, error) {
+	return &channeltypes.QueryChannelsResponse{
+		Channels: []*channeltypes.IdentifiedChannel{
+			{
+				PortId:         "transfer",
+				ChannelId:      "channel-0",
+				State:          channeltypes.OPEN,
+				ConnectionHops: []string{"connection-0"},
+				Counterparty:   channeltypes.Counterparty{PortId: "transfer", ChannelId: "channel-5"},
+			},
+			{
+				PortId:         "transfer",
+				ChannelId:      "channel-1",
+				State:          channeltypes.CLOSED,
+				ConnectionHops: []string{"connection-0"},
+			},
+		},
+	}, nil
+}
+
+func (*ibcChannelServer) PacketCommitments(
+	_ context.Context,
+	_ *channeltypes.QueryPacketCommitmentsRequest,
+) (*channeltypes.QueryPacketCommitmentsResponse, error) {
+	return &channeltypes.QueryPacketCommitmentsResponse{
+		Commitments: []*channeltypes.PacketState{{Sequence: 1}, {Sequence: 2}, {Sequence: 3}},
+	}, nil
+}
+
+func (*ibcChannelServer) PacketAcknowledgements(
+	_ context.Context,
+	req *channeltypes.QueryPacketAcknowledgementsRequest,
+) (*channeltypes.QueryPacketAcknowledgementsResponse, error) {
+	if len(req.GetPacketCommitmentSequences()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "acknowledgements requested without commitment sequences")
+	}
+
+	// Every packet but the last one was received and acknowledged
+	acks := []*channeltypes.PacketState{}
+	for _, sequence := range req.GetPacketCommitmentSequences()[:len(req.GetPacketCommitmentSequences())-1] {
+		acks = append(acks, &channeltypes.PacketState{Sequence: sequence})
+	}
+
+	return &channeltypes.QueryPacketAcknowledgementsResponse{Acknowledgements: acks}, nil
+}
+
+func (*ibcChannelServer) UnreceivedPackets(
+	_ context.Context,
+	req *channeltypes.QueryUnreceivedPacketsRequest,
+) (*channeltypes.QueryUnreceivedPacketsResponse, error) {
+	// The counterparty received every packet but the first one
+	return &channeltypes.QueryUnreceivedPacketsResponse{
+		Sequences: req.GetPacketCommitmentSequences()[1:],
+	}, nil
+}
+
+func (*ibcChannelServer) UnreceivedAcks(
+	_ context.Context,
+	req *channeltypes.QueryUnreceivedAcksRequest,
+) (*channeltypes.QueryUnreceivedAcksResponse, error) {
+	// The counterparty received the acknowledgement of the first packet only
+	return &channeltypes.QueryUnreceivedAcksResponse{Sequences: req.GetPacketAckSequences()[1:]}, nil
+}
+
+func metricLabels(m *dto.Metric) map[string]string {
+	labels := map[string]string{}
+	for _, label := range m.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+
+	return labels
+}
+
+// TestIBCCollect tests that clients, connections and channels are exported
+// with their counterparty chain and that only open channels get packet
+// metrics.
+func TestIBCCollect(t *testing.T) {
+	cfg := newTestServer(t, func(server *googlegrpc.Server) {
+		clienttypes.RegisterQueryServer(server, &ibcClientServer{timestamp: time.Now().Add(-30 * time.Minute)})
+		connectiontypes.RegisterQueryServer(server, &ibcConnectionServer{})
+		channeltypes.RegisterQueryServer(server, &ibcChannelServer{})
+	})
+
+	c := IBCCollector{
+		Cfg: cfg,
+		Counterparties: map[string]config.NodeEndpoint{
+			"osmosis-1": {Name: "osmosis-1", Addr: cfg.GRPCAddr},
+		},
+	}
+
+	metrics := collectGauges(t, c)
+
+	if got := metrics[ibcClientLatestHeight][0].GetGauge().GetValue(); got != 100 {
+		t.Errorf("expected client latest height 100, got %v", got)
+	}
+
+	remaining := metrics[ibcClientTrustingRemaining][0].GetGauge().GetValue()
+	if remaining < 25*60 || remaining > 30*60 {
+		t.Errorf("expected about 30 minutes of trusting period left, got %v seconds", remaining)
+	}
+
+	if got := metrics[ibcClientActive][0].GetGauge().GetValue(); got != 1 {
+		t.Errorf("expected client to be active, got %v", got)
+	}
+
+	states := map[string]string{}
+	for _, m := range metrics[ibcChannelState] {
+		labels := metricLabels(m)
+		states[labels["channel_id"]] = labels["state"]
+		if labels["counterparty_chain_id"] != "osmosis-1" {
+			t.Errorf("expected counterparty chain osmosis-1, got %s", labels["counterparty_chain_id"])
+		}
+	}
+	if states["channel-0"] != "open" || states["channel-1"] != "closed" {
+		t.Errorf("unexpected channel states %v", states)
+	}
+
+	expected := map[*prometheus.Desc]float64{
+		ibcChannelPacketCommitments: 3,
+		ibcChannelUnreceivedPackets: 2,
+		ibcChannelUnreceivedAcks:    1,
+	}
+	for desc, value := range expected {
+		series := metrics[desc]
+		if len(series) != 1 {
+			t.Fatalf("expected one series for %s, got %d", desc, len(series))
+		}
+		if got := series[0].GetGauge().GetValue(); got != value {
+			t.Errorf("expected %v for %s, got %v", value, desc, got)
+		}
+		if status := metricLabels(series[0])["status"]; status != successStatus {
+			t.Errorf("expected status %s for %s, got %s", successStatus, desc, status)
+		}
+	}
+}
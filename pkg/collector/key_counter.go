@@ -0,0 +1,417 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	warden "github.com/warden-protocol/wardenprotocol/warden/x/warden/types/v1beta3"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	checkpointFileMode               = 0o600
+	wardenKeysMetricName             = "warden_keys"
+	wardenKeyCountSyncedMetricName   = "warden_key_count_synced"
+	wardenKeyCountCheckpointName     = "warden_key_count_checkpoint"
+	wardenKeyCountDurationMetricName = "warden_key_count_last_run_duration_seconds"
+	wardenKeyCountPagesMetricName    = "warden_key_count_last_run_pages"
+	wardenKeyCountPagesTotalName     = "warden_key_count_pages_total"
+	wardenKeyCountLastSuccessName    = "warden_key_count_last_success_timestamp_seconds"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	wardenKeys = prometheus.NewDesc(
+		wardenKeysMetricName,
+		"Returns the number of keys by key type",
+		[]string{
+			"chain_id",
+			"key_type",
+		},
+		nil,
+	)
+
+	wardenKeyCountSynced = prometheus.NewDesc(
+		wardenKeyCountSyncedMetricName,
+		"Returns 1 once the background key count has caught up with the chain, 0 while it is still paging "+
+			"through existing keys",
+		[]string{
+			"chain_id",
+		},
+		nil,
+	)
+
+	wardenKeyCountCheckpoint = prometheus.NewDesc(
+		wardenKeyCountCheckpointName,
+		"Returns the id of the next key the background key count will fetch",
+		[]string{
+			"chain_id",
+		},
+		nil,
+	)
+
+	wardenKeyCountDuration = prometheus.NewDesc(
+		wardenKeyCountDurationMetricName,
+		"Returns the duration in seconds of the last background key count run",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	wardenKeyCountPages = prometheus.NewDesc(
+		wardenKeyCountPagesMetricName,
+		"Returns the number of key pages fetched by the last background key count run",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	wardenKeyCountPagesTotal = prometheus.NewDesc(
+		wardenKeyCountPagesTotalName,
+		"Returns the number of key pages fetched by the background key count since the exporter started",
+		[]string{
+			"chain_id",
+		},
+		nil,
+	)
+
+	wardenKeyCountLastSuccess = prometheus.NewDesc(
+		wardenKeyCountLastSuccessName,
+		"Returns the time of the last successful background key count run as a unix timestamp",
+		[]string{
+			"chain_id",
+		},
+		nil,
+	)
+)
+
+// keyCheckpoint is the state a KeyCounter resumes from. Keys are never
+// deleted and their type and space do not change, so counts only grow and
+// only keys from NextID on need to be fetched.
+type keyCheckpoint struct {
+	NextID  uint64            `json:"next_id"`
+	ByType  map[string]uint64 `json:"by_type"`
+	BySpace map[uint64]uint64 `json:"by_space"`
+}
+
+type keyCountRun struct {
+	duration float64
+	pages    int
+	finished time.Time
+	err      error
+}
+
+// KeyCounter counts Warden keys in the background, fetching only the keys
+// created since the previous run in pages of WARDEN_KEY_PAGE_SIZE keys. The
+// checkpoint is kept in memory and, with WARDEN_KEY_CHECKPOINT_FILE, on disk
+// so a restart does not page through every key again. It is shared between
+// scrapes, so collectors hold it by pointer.
+type KeyCounter struct {
+	cfg         config.Config
+	mu          sync.Mutex
+	checkpoint  keyCheckpoint
+	synced      bool
+	lastRun     keyCountRun
+	lastSuccess time.Time
+	totalPages  uint64
+}
+
+func NewKeyCounter(cfg config.Config) *KeyCounter {
+	return &KeyCounter{
+		cfg: cfg,
+		checkpoint: keyCheckpoint{
+			ByType:  map[string]uint64{},
+			BySpace: map[uint64]uint64{},
+		},
+	}
+}
+
+// Load restores the checkpoint from WARDEN_KEY_CHECKPOINT_FILE. A missing file
+// is not an error.
+func (k *KeyCounter) Load() error {
+	if k.cfg.WardenKeyCheckpointFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(k.cfg.WardenKeyCheckpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading key checkpoint: %w", err)
+	}
+
+	checkpoint := keyCheckpoint{}
+	if err = json.Unmarshal(data, &checkpoint); err != nil {
+		return fmt.Errorf("error decoding key checkpoint: %w", err)
+	}
+	if checkpoint.ByType == nil {
+		checkpoint.ByType = map[string]uint64{}
+	}
+	if checkpoint.BySpace == nil {
+		checkpoint.BySpace = map[uint64]uint64{}
+	}
+
+	k.mu.Lock()
+	k.checkpoint = checkpoint
+	k.mu.Unlock()
+
+	log.Info(fmt.Sprintf("Resuming key count from key %d", checkpoint.NextID))
+
+	return nil
+}
+
+// Run counts new keys every WARDEN_KEY_COUNT_INTERVAL seconds until ctx is
+// done.
+func (k *KeyCounter) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(max(k.cfg.WardenKeyCountInterval, 1)) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		k.count(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// count fetches the keys created since the checkpoint. The checkpoint moves
+// after every page, so a failed run resumes where it stopped.
+func (k *KeyCounter) count(ctx context.Context) {
+	start := time.Now()
+	pages := 0
+
+	err := func() error {
+		client, err := grpc.NewClient(k.cfg)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if tempErr := client.CloseConn(); tempErr != nil {
+				log.Error(tempErr.Error())
+			}
+		}()
+
+		for {
+			pageCtx, cancel := context.WithTimeout(ctx, time.Duration(k.cfg.Timeout)*time.Second)
+			keys, more, pageErr := client.KeysPage(pageCtx, k.nextID(), uint64(k.cfg.WardenKeyPageSize))
+			cancel()
+			if pageErr != nil {
+				return pageErr
+			}
+
+			pages++
+			k.add(keys)
+			k.saveCheckpoint()
+
+			if !more {
+				return nil
+			}
+		}
+	}()
+
+	k.mu.Lock()
+	k.lastRun = keyCountRun{
+		duration: time.Since(start).Seconds(),
+		pages:    pages,
+		finished: time.Now(),
+		err:      err,
+	}
+	k.totalPages += uint64(pages)
+	if err == nil {
+		k.synced = true
+		k.lastSuccess = k.lastRun.finished
+	}
+	nextID := k.checkpoint.NextID
+	k.mu.Unlock()
+
+	if err != nil {
+		log.Error(fmt.Sprintf("error counting keys: %s", err))
+		return
+	}
+
+	log.Debug(fmt.Sprintf("Counted keys up to key %d in %d pages", nextID, pages))
+}
+
+// saveCheckpoint writes the current checkpoint to WARDEN_KEY_CHECKPOINT_FILE,
+// so a restart after a failed run does not lose the pages already counted.
+func (k *KeyCounter) saveCheckpoint() {
+	k.mu.Lock()
+	checkpoint := k.copyCheckpoint()
+	k.mu.Unlock()
+
+	if err := k.save(checkpoint); err != nil {
+		log.Error(err.Error())
+	}
+}
+
+func (k *KeyCounter) nextID() uint64 {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.checkpoint.NextID
+}
+
+func (k *KeyCounter) add(keys []warden.Key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, key := range keys {
+		// Skip keys before the checkpoint in case a page overlaps
+		if key.Id < k.checkpoint.NextID {
+			continue
+		}
+
+		k.checkpoint.ByType[keyTypeLabel(key.Type)]++
+		k.checkpoint.BySpace[key.SpaceId]++
+		k.checkpoint.NextID = key.Id + 1
+	}
+}
+
+func (k *KeyCounter) save(checkpoint keyCheckpoint) error {
+	if k.cfg.WardenKeyCheckpointFile == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("error encoding key checkpoint: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a partial
+	// checkpoint behind
+	tmp := k.cfg.WardenKeyCheckpointFile + ".tmp"
+	if err = os.WriteFile(tmp, data, checkpointFileMode); err != nil {
+		return fmt.Errorf("error writing key checkpoint: %w", err)
+	}
+
+	if err = os.Rename(tmp, k.cfg.WardenKeyCheckpointFile); err != nil {
+		return fmt.Errorf("error writing key checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// copyCheckpoint returns a copy of the checkpoint, the caller must hold mu.
+func (k *KeyCounter) copyCheckpoint() keyCheckpoint {
+	checkpoint := keyCheckpoint{
+		NextID:  k.checkpoint.NextID,
+		ByType:  make(map[string]uint64, len(k.checkpoint.ByType)),
+		BySpace: make(map[uint64]uint64, len(k.checkpoint.BySpace)),
+	}
+	for keyType, count := range k.checkpoint.ByType {
+		checkpoint.ByType[keyType] = count
+	}
+	for spaceID, count := range k.checkpoint.BySpace {
+		checkpoint.BySpace[spaceID] = count
+	}
+
+	return checkpoint
+}
+
+// keysPerSpace returns the number of keys of every space that has keys. The
+// second return value is false until a full pass over all keys completed.
+func (k *KeyCounter) keysPerSpace() (map[uint64]uint64, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.copyCheckpoint().BySpace, k.synced
+}
+
+func (w WardenCollector) collectKeyCountMetrics(ch chan<- prometheus.Metric) {
+	w.Keys.mu.Lock()
+	checkpoint := w.Keys.copyCheckpoint()
+	synced, lastRun, lastSuccess, totalPages := w.Keys.synced, w.Keys.lastRun, w.Keys.lastSuccess, w.Keys.totalPages
+	w.Keys.mu.Unlock()
+
+	for _, keyType := range keyTypes {
+		ch <- prometheus.MustNewConstMetric(
+			wardenKeys,
+			prometheus.GaugeValue,
+			float64(checkpoint.ByType[keyTypeLabel(keyType)]),
+			[]string{w.Cfg.ChainID, keyTypeLabel(keyType)}...,
+		)
+	}
+
+	syncedValue := 0.0
+	if synced {
+		syncedValue = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeyCountSynced,
+		prometheus.GaugeValue,
+		syncedValue,
+		[]string{w.Cfg.ChainID}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeyCountCheckpoint,
+		prometheus.GaugeValue,
+		float64(checkpoint.NextID),
+		[]string{w.Cfg.ChainID}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeyCountPagesTotal,
+		prometheus.CounterValue,
+		float64(totalPages),
+		[]string{w.Cfg.ChainID}...,
+	)
+
+	// Nothing has run yet right after startup
+	if lastRun.finished.IsZero() {
+		return
+	}
+
+	status := successStatus
+	if lastRun.err != nil {
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeyCountDuration,
+		prometheus.GaugeValue,
+		lastRun.duration,
+		[]string{w.Cfg.ChainID, status}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeyCountPages,
+		prometheus.GaugeValue,
+		float64(lastRun.pages),
+		[]string{w.Cfg.ChainID, status}...,
+	)
+
+	if !lastSuccess.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			wardenKeyCountLastSuccess,
+			prometheus.GaugeValue,
+			float64(lastSuccess.Unix()),
+			[]string{w.Cfg.ChainID}...,
+		)
+	}
+}
+
+// keyTypeLabel turns a key type such as KEY_TYPE_ECDSA_SECP256K1 into a label
+// value such as ecdsa_secp256k1.
+func keyTypeLabel(keyType warden.KeyType) string {
+	return requestStatusLabel(keyType.String(), keyTypePrefix)
+}
@@ -0,0 +1,180 @@
+package collector
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	warden "github.com/warden-protocol/wardenprotocol/warden/x/warden/types/v1beta3"
+	googlegrpc "google.golang.org/grpc"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc/grpctest"
+)
+
+type keysServer struct {
+	warden.UnimplementedQueryServer
+	mu   sync.Mutex
+	keys []warden.Key
+	// failFrom makes pages starting at or after this key id fail
+	failFrom uint64
+}
+
+func (s *keysServer) AllKeys(
+	_ context.Context,
+	req *warden.QueryAllKeysRequest,
+) (*warden.QueryKeysResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var start uint64
+	if len(req.GetPagination().GetKey()) == 8 {
+		start = binary.BigEndian.Uint64(req.GetPagination().GetKey())
+	}
+	if s.failFrom > 0 && start >= s.failFrom {
+		return nil, errors.New("node unavailable")
+	}
+
+	res := &warden.QueryKeysResponse{Pagination: &query.PageResponse{}}
+	for _, key := range s.keys {
+		if key.Id < start {
+			continue
+		}
+		if uint64(len(res.Keys)) == req.GetPagination().GetLimit() {
+			res.Pagination.NextKey = binary.BigEndian.AppendUint64(nil, key.Id)
+			break
+		}
+		res.Keys = append(res.Keys, warden.QueryKeyResponse{Key: key})
+	}
+
+	return res, nil
+}
+
+func (s *keysServer) addKeys(keys ...warden.Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = append(s.keys, keys...)
+}
+
+// newTestServer serves the query servers registered by register on a local
+// listener and returns a config pointing at it.
+func newTestServer(
+	t *testing.T,
+	register func(*googlegrpc.Server),
+	opts ...googlegrpc.ServerOption,
+) config.Config {
+	t.Helper()
+
+	return config.Config{
+		ChainID:  "warden_8765-1",
+		GRPCAddr: grpctest.NewServer(t, register, opts...),
+		Timeout:  5,
+	}
+}
+
+// newTestClient returns a client connected to the node of cfg.
+func newTestClient(t *testing.T, cfg config.Config) grpc.Client {
+	t.Helper()
+
+	client, err := grpc.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("error creating client: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = client.CloseConn()
+	})
+
+	return client
+}
+
+// TestKeyCounterCount tests that keys are counted in pages, that a second run
+// only fetches the keys created since the checkpoint and that the checkpoint
+// file restores the counts.
+func TestKeyCounterCount(t *testing.T) {
+	keys := &keysServer{}
+	keys.addKeys(
+		warden.Key{Id: 1, SpaceId: 1, Type: warden.KeyType_KEY_TYPE_ECDSA_SECP256K1},
+		warden.Key{Id: 2, SpaceId: 1, Type: warden.KeyType_KEY_TYPE_ECDSA_SECP256K1},
+		warden.Key{Id: 3, SpaceId: 2, Type: warden.KeyType_KEY_TYPE_EDDSA_ED25519},
+	)
+
+	// The key requests only encode with their gogoproto methods
+	cfg := newTestServer(t, func(server *googlegrpc.Server) {
+		warden.RegisterQueryServer(server, keys)
+	}, grpctest.GogoCodec())
+	cfg.WardenKeyPageSize = 2
+	cfg.WardenKeyCheckpointFile = filepath.Join(t.TempDir(), "keys.json")
+
+	counter := NewKeyCounter(cfg)
+	counter.count(context.Background())
+
+	if counter.lastRun.err != nil || counter.lastRun.pages != 2 {
+		t.Fatalf("expected 2 pages without error, got %d pages and %v", counter.lastRun.pages, counter.lastRun.err)
+	}
+
+	keys.addKeys(warden.Key{Id: 4, SpaceId: 2, Type: warden.KeyType_KEY_TYPE_ECDSA_SECP256K1})
+	counter.count(context.Background())
+
+	if counter.lastRun.pages != 1 {
+		t.Errorf("expected 1 page for the new key, got %d", counter.lastRun.pages)
+	}
+
+	restored := NewKeyCounter(cfg)
+	if err := restored.Load(); err != nil {
+		t.Fatalf("error loading checkpoint: %s", err)
+	}
+
+	for _, c := range []*KeyCounter{counter, restored} {
+		checkpoint := c.copyCheckpoint()
+		if checkpoint.NextID != 5 {
+			t.Errorf("expected next id 5, got %d", checkpoint.NextID)
+		}
+		if checkpoint.ByType["ecdsa_secp256k1"] != 3 || checkpoint.ByType["eddsa_ed25519"] != 1 {
+			t.Errorf("unexpected counts by type %v", checkpoint.ByType)
+		}
+		if checkpoint.BySpace[1] != 2 || checkpoint.BySpace[2] != 2 {
+			t.Errorf("unexpected counts by space %v", checkpoint.BySpace)
+		}
+	}
+}
+
+// TestKeyCounterCountSavesProgress tests that the pages counted before a
+// failing page are kept in the checkpoint file.
+func TestKeyCounterCountSavesProgress(t *testing.T) {
+	keys := &keysServer{failFrom: 3}
+	keys.addKeys(
+		warden.Key{Id: 1, SpaceId: 1, Type: warden.KeyType_KEY_TYPE_ECDSA_SECP256K1},
+		warden.Key{Id: 2, SpaceId: 1, Type: warden.KeyType_KEY_TYPE_ECDSA_SECP256K1},
+		warden.Key{Id: 3, SpaceId: 2, Type: warden.KeyType_KEY_TYPE_EDDSA_ED25519},
+	)
+
+	// The key requests only encode with their gogoproto methods
+	cfg := newTestServer(t, func(server *googlegrpc.Server) {
+		warden.RegisterQueryServer(server, keys)
+	}, grpctest.GogoCodec())
+	cfg.WardenKeyPageSize = 2
+	cfg.WardenKeyCheckpointFile = filepath.Join(t.TempDir(), "keys.json")
+
+	counter := NewKeyCounter(cfg)
+	counter.count(context.Background())
+
+	if counter.lastRun.err == nil || counter.lastRun.pages != 1 {
+		t.Fatalf("expected an error after 1 page, got %d pages and %v", counter.lastRun.pages, counter.lastRun.err)
+	}
+
+	restored := NewKeyCounter(cfg)
+	if err := restored.Load(); err != nil {
+		t.Fatalf("error loading checkpoint: %s", err)
+	}
+
+	checkpoint := restored.copyCheckpoint()
+	if checkpoint.NextID != 3 || checkpoint.ByType["ecdsa_secp256k1"] != 2 {
+		t.Errorf("expected the first page in the checkpoint, got %+v", checkpoint)
+	}
+}
@@ -36,6 +36,7 @@ var messariCredits = prometheus.NewDesc(
 	messariCreditMetricName,
 	"Returns Messari API Key credit information",
 	[]string{
+		"account",
 		"team_id",
 		"is_active",
 		"credits_allocated",
@@ -53,16 +54,25 @@ func (m MessariCollector) Describe(ch chan<- *prometheus.Desc) {
 }
 
 func (m MessariCollector) Collect(ch chan<- prometheus.Metric) {
-	var err error
 	ctx, cancel := context.WithTimeout(
 		context.Background(),
 		time.Duration(m.Cfg.Timeout)*time.Second,
 	)
 	defer cancel()
 
+	for _, account := range config.ParseAPIAccounts(m.Cfg.MessariAPIKey) {
+		m.collectAccount(ctx, ch, account)
+	}
+}
+
+func (m MessariCollector) collectAccount(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+) {
 	status := successStatus
 
-	response, err := m.messariCollectCredits(ctx)
+	response, err := m.messariCollectCredits(ctx, account.Key)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting Messari credits %s", err))
 		status = errorStatus
@@ -73,6 +83,7 @@ func (m MessariCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(response.Data.RemainingCredits),
 		[]string{
+			account.Name,
 			fmt.Sprintf("%d", response.Data.TeamID),
 			fmt.Sprintf("%t", response.Data.IsActive),
 			fmt.Sprintf("%d", response.Data.CreditsAllocated),
@@ -83,9 +94,10 @@ func (m MessariCollector) Collect(ch chan<- prometheus.Metric) {
 
 func (m MessariCollector) messariCollectCredits(
 	ctx context.Context,
+	apiKey string,
 ) (MessariCreditResponse, error) {
 	url := fmt.Sprintf("%s/user-management/v1/credits/allowance", messariAPIURL)
-	data, err := http.GetRequest(ctx, url, m.Cfg.MessariAPIKey, m.Cfg.HTTPTimeout)
+	data, err := http.GetRequest(ctx, url, apiKey, m.Cfg.HTTPTimeout)
 	if err != nil {
 		return MessariCreditResponse{}, err
 	}
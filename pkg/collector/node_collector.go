@@ -0,0 +1,152 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	nodeLatestHeightMetricName    = "cosmos_node_latest_block_height"
+	nodeLatestBlockTimeMetricName = "cosmos_node_latest_block_time_seconds"
+	nodeHeightLagMetricName       = "cosmos_node_height_lag"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	nodeLatestHeight = prometheus.NewDesc(
+		nodeLatestHeightMetricName,
+		"Returns the latest block height of the node",
+		[]string{
+			"chain_id",
+			"node",
+			"status",
+		},
+		nil,
+	)
+
+	nodeLatestBlockTime = prometheus.NewDesc(
+		nodeLatestBlockTimeMetricName,
+		"Returns the time of the latest block of the node as a unix timestamp",
+		[]string{
+			"chain_id",
+			"node",
+			"status",
+		},
+		nil,
+	)
+
+	nodeHeightLag = prometheus.NewDesc(
+		nodeHeightLagMetricName,
+		"Returns the number of blocks the node is behind the highest height observed across all nodes",
+		[]string{
+			"chain_id",
+			"node",
+			"status",
+		},
+		nil,
+	)
+)
+
+type nodeResult struct {
+	node  config.NodeEndpoint
+	block grpc.BlockStats
+	err   error
+}
+
+// NodesCollector compares the latest block of every node in NODE_ENDPOINTS.
+type NodesCollector struct {
+	Cfg   config.Config
+	Nodes []config.NodeEndpoint
+}
+
+func (n NodesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nodeLatestHeight
+	ch <- nodeLatestBlockTime
+	ch <- nodeHeightLag
+}
+
+func (n NodesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(n.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	// Query the nodes concurrently so a slow node does not delay the others
+	results := make([]nodeResult, len(n.Nodes))
+	var wg sync.WaitGroup
+	for i, node := range n.Nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			block, err := grpc.LatestBlock(ctx, n.Cfg.ForNode(node))
+			if err != nil {
+				log.Error(fmt.Sprintf("error getting latest block of node %s: %s", node.Name, err))
+			}
+			results[i] = nodeResult{node: node, block: block, err: err}
+		}()
+	}
+	wg.Wait()
+
+	lags := nodeHeightLags(results)
+
+	for _, result := range results {
+		status := successStatus
+		blockTime := 0.0
+		if result.err != nil {
+			status = errorStatus
+		} else {
+			blockTime = float64(result.block.Time.UnixNano()) / float64(time.Second)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			nodeLatestHeight,
+			prometheus.GaugeValue,
+			float64(result.block.Height),
+			[]string{n.Cfg.ChainID, result.node.Name, status}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			nodeLatestBlockTime,
+			prometheus.GaugeValue,
+			blockTime,
+			[]string{n.Cfg.ChainID, result.node.Name, status}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			nodeHeightLag,
+			prometheus.GaugeValue,
+			float64(lags[result.node.Name]),
+			[]string{n.Cfg.ChainID, result.node.Name, status}...,
+		)
+	}
+}
+
+// nodeHeightLags returns how many blocks every node that responded is behind
+// the highest height among them.
+func nodeHeightLags(results []nodeResult) map[string]int64 {
+	var highest int64
+	for _, result := range results {
+		if result.err == nil {
+			highest = max(highest, result.block.Height)
+		}
+	}
+
+	lags := map[string]int64{}
+	for _, result := range results {
+		if result.err == nil {
+			lags[result.node.Name] = highest - result.block.Height
+		}
+	}
+
+	return lags
+}
@@ -0,0 +1,25 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+)
+
+// TestNodeHeightLags tests that lags are measured against the highest height
+// of the nodes that responded and that failed nodes get no lag.
+func TestNodeHeightLags(t *testing.T) {
+	results := []nodeResult{
+		{node: config.NodeEndpoint{Name: "sentry-0"}, block: grpc.BlockStats{Height: 100}},
+		{node: config.NodeEndpoint{Name: "sentry-1"}, block: grpc.BlockStats{Height: 97}},
+		{node: config.NodeEndpoint{Name: "archive"}, err: errors.New("unavailable")},
+	}
+
+	lags := nodeHeightLags(results)
+
+	if len(lags) != 2 || lags["sentry-0"] != 0 || lags["sentry-1"] != 3 {
+		t.Errorf("nodeHeightLags() = %v, want sentry-0=0 sentry-1=3", lags)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,29 +18,108 @@ import (
 )
 
 const (
-	openAICostMetricName = "openai_cost"
-	openAIAPIURL         = "https://api.openai.com/v1"
+	openAICostMetricName          = "openai_cost"
+	openAICostByProjectMetricName = "openai_cost_by_project"
+	openAIUsageTokensMetricName   = "openai_usage_tokens"
+	openAIUsageRequestsMetricName = "openai_usage_requests"
+	openAIMaxPages                = 50
 )
 
 type OpenAICostsResponse struct {
 	Data []struct {
 		Results []struct {
 			Amount struct {
-				Value json.Number `json:"value"`
+				Value    json.Number `json:"value"`
+				Currency string      `json:"currency"`
 			} `json:"amount"`
+			LineItem  *string `json:"line_item"`
+			ProjectID *string `json:"project_id"`
 		} `json:"results"`
 	} `json:"data"`
+	HasMore  bool   `json:"has_more"`
+	NextPage string `json:"next_page"`
+}
+
+type OpenAICompletionsUsageResponse struct {
+	Data []struct {
+		Results []struct {
+			InputTokens       int64   `json:"input_tokens"`
+			OutputTokens      int64   `json:"output_tokens"`
+			InputCachedTokens int64   `json:"input_cached_tokens"`
+			NumModelRequests  int64   `json:"num_model_requests"`
+			Model             *string `json:"model"`
+		} `json:"results"`
+	} `json:"data"`
+	HasMore  bool   `json:"has_more"`
+	NextPage string `json:"next_page"`
+}
+
+type openAICostKey struct {
+	projectID string
+	lineItem  string
+	currency  string
+}
+
+type openAICosts struct {
+	total     float64
+	breakdown map[openAICostKey]float64
+}
+
+type openAIModelUsage struct {
+	inputTokens       float64
+	outputTokens      float64
+	inputCachedTokens float64
+	requests          float64
 }
 
 //nolint:gochecknoglobals // this is needed as it's used in multiple places
-var openAICost = prometheus.NewDesc(
-	openAICostMetricName,
-	"Returns OpenAI API costs",
-	[]string{
-		"currency",
-		"status",
-	},
-	nil,
+var (
+	openAICost = prometheus.NewDesc(
+		openAICostMetricName,
+		"Returns OpenAI API costs",
+		[]string{
+			"account",
+			"currency",
+			"status",
+		},
+		nil,
+	)
+
+	openAICostByProject = prometheus.NewDesc(
+		openAICostByProjectMetricName,
+		"Returns OpenAI API month-to-date costs by project and line item",
+		[]string{
+			"account",
+			"project_id",
+			"line_item",
+			"currency",
+			"status",
+		},
+		nil,
+	)
+
+	openAIUsageTokens = prometheus.NewDesc(
+		openAIUsageTokensMetricName,
+		"Returns OpenAI API month-to-date completion tokens by model and token type",
+		[]string{
+			"account",
+			"model",
+			"type",
+			"status",
+		},
+		nil,
+	)
+
+	openAIUsageRequests = prometheus.NewDesc(
+		openAIUsageRequestsMetricName,
+		"Returns OpenAI API month-to-date completion requests by model",
+		[]string{
+			"account",
+			"model",
+			"status",
+		},
+		nil,
+	)
 )
 
 type OpenAICollector struct {
@@ -47,91 +128,279 @@ type OpenAICollector struct {
 
 func (o OpenAICollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- openAICost
+	ch <- openAICostByProject
+	ch <- openAIUsageTokens
+	ch <- openAIUsageRequests
 }
 
 func (o OpenAICollector) Collect(ch chan<- prometheus.Metric) {
+	o.collectFailures(ch)
+}
+
+// collectFailures reports the accounts with failed requests, some of which,
+// like the usage breakdown, leave no series behind.
+func (o OpenAICollector) collectFailures(ch chan<- prometheus.Metric) []string {
 	ctx, cancel := context.WithTimeout(
 		context.Background(),
 		time.Duration(o.Cfg.Timeout)*time.Second,
 	)
 	defer cancel()
 
+	var failed []string
+	for _, account := range config.ParseAPIAccounts(o.Cfg.OpenAIAPIKey) {
+		if errors := o.collectAccount(ctx, ch, account); len(errors) > 0 {
+			failed = append(failed, account.Name)
+		}
+	}
+
+	return failed
+}
+
+func (o OpenAICollector) collectAccount(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+) []string {
 	var errors []string
 
-	errors = o.collectCostMetrics(ctx, ch, errors)
+	errors = o.collectCostMetrics(ctx, ch, account, errors)
+	errors = o.collectUsageMetrics(ctx, ch, account, errors)
 
 	if len(errors) > 0 {
-		log.Info(fmt.Sprintf("OpenAI metrics collection completed with errors: %v", errors))
+		log.Info(fmt.Sprintf("OpenAI metrics collection for account %s completed with errors: %v", account.Name, errors))
 	} else {
-		log.Info("OpenAI metrics collection completed successfully")
+		log.Info(fmt.Sprintf("OpenAI metrics collection for account %s completed successfully", account.Name))
 	}
+
+	return errors
 }
 
 func (o OpenAICollector) collectCostMetrics(
 	ctx context.Context,
 	ch chan<- prometheus.Metric,
+	account config.APIAccount,
 	errors []string,
 ) []string {
 	monthlyCostStatus := successStatus
-	monthlyCost, err := o.openAICollectCostsMonthly(ctx)
+	monthlyCosts, err := o.openAICollectCostsMonthly(ctx, account.Key)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting OpenAI monthly costs: %s", err))
 		errors = append(errors, "monthly costs")
 		monthlyCostStatus = errorStatus
-		monthlyCost = 0
+		monthlyCosts = openAICosts{}
 	}
 
 	ch <- prometheus.MustNewConstMetric(
 		openAICost,
 		prometheus.GaugeValue,
-		monthlyCost,
-		[]string{"USD", monthlyCostStatus}...,
+		monthlyCosts.total,
+		[]string{account.Name, "USD", monthlyCostStatus}...,
 	)
 
+	for key, value := range monthlyCosts.breakdown {
+		ch <- prometheus.MustNewConstMetric(
+			openAICostByProject,
+			prometheus.GaugeValue,
+			value,
+			[]string{account.Name, key.projectID, key.lineItem, key.currency, monthlyCostStatus}...,
+		)
+	}
+
+	return errors
+}
+
+func (o OpenAICollector) collectUsageMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+	errors []string,
+) []string {
+	usageStatus := successStatus
+	usage, err := o.openAICollectCompletionsUsageMonthly(ctx, account.Key)
+	if err != nil {
+		log.Error(fmt.Sprintf("error collecting OpenAI completions usage: %s", err))
+		errors = append(errors, "completions usage")
+		usageStatus = errorStatus
+		usage = map[string]openAIModelUsage{}
+	}
+
+	for model, u := range usage {
+		tokenTypes := []struct {
+			tokenType string
+			value     float64
+		}{
+			{"input", u.inputTokens},
+			{"output", u.outputTokens},
+			{"cached_input", u.inputCachedTokens},
+		}
+		for _, t := range tokenTypes {
+			ch <- prometheus.MustNewConstMetric(
+				openAIUsageTokens,
+				prometheus.GaugeValue,
+				t.value,
+				[]string{account.Name, model, t.tokenType, usageStatus}...,
+			)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			openAIUsageRequests,
+			prometheus.GaugeValue,
+			u.requests,
+			[]string{account.Name, model, usageStatus}...,
+		)
+	}
+
 	return errors
 }
 
-func (o OpenAICollector) openAICollectCostsMonthly(ctx context.Context) (float64, error) {
+func (o OpenAICollector) openAICollectCostsMonthly(
+	ctx context.Context,
+	apiKey string,
+) (openAICosts, error) {
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return o.openAICollectCosts(ctx, apiKey, monthStart, now)
+}
+
+func (o OpenAICollector) openAICollectCompletionsUsageMonthly(
+	ctx context.Context,
+	apiKey string,
+) (map[string]openAIModelUsage, error) {
 	now := time.Now().UTC()
 	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
 
-	return o.openAICollectCosts(ctx, monthStart, now)
+	return o.openAICollectCompletionsUsage(ctx, apiKey, monthStart, now)
 }
 
 func (o OpenAICollector) openAICollectCosts(
 	ctx context.Context,
+	apiKey string,
 	startTime, endTime time.Time,
-) (float64, error) {
+) (openAICosts, error) {
 	days := int(endTime.Sub(startTime)/(24*time.Hour)) + 1
 
-	url := fmt.Sprintf("%s/organization/costs?start_time=%d&limit=%d",
-		openAIAPIURL, startTime.Unix(), days)
+	query := url.Values{}
+	query.Set("start_time", strconv.FormatInt(startTime.Unix(), 10))
+	query.Set("limit", strconv.Itoa(days))
+	query.Add("group_by", "project_id")
+	query.Add("group_by", "line_item")
 
-	data, err := http.GetRequest(ctx, url, o.Cfg.OpenAIAPIKey, o.Cfg.HTTPTimeout)
-	if err != nil {
-		return 0, err
-	}
+	costs := openAICosts{breakdown: make(map[openAICostKey]float64)}
 
-	decoder := json.NewDecoder(bytes.NewReader(data))
-	decoder.UseNumber()
+	page := ""
+	for range openAIMaxPages {
+		if page != "" {
+			query.Set("page", page)
+		}
 
-	var costsResponse OpenAICostsResponse
-	if err = decoder.Decode(&costsResponse); err != nil {
-		return 0, fmt.Errorf("error decoding response: %w", err)
+		data, err := o.openAIGet(ctx, apiKey, "/organization/costs", query)
+		if err != nil {
+			return openAICosts{}, err
+		}
+
+		var costsResponse OpenAICostsResponse
+		if err = decodeOpenAIResponse(data, &costsResponse); err != nil {
+			return openAICosts{}, err
+		}
+
+		for _, bucket := range costsResponse.Data {
+			for _, res := range bucket.Results {
+				valueStr := string(res.Amount.Value)
+				value, parseErr := strconv.ParseFloat(valueStr, 64)
+				if parseErr != nil {
+					log.Error(fmt.Sprintf("error parsing amount value '%s': %s", valueStr, parseErr))
+					continue
+				}
+				costs.total += value
+
+				key := openAICostKey{
+					projectID: groupValue(res.ProjectID),
+					lineItem:  groupValue(res.LineItem),
+					currency:  strings.ToUpper(res.Amount.Currency),
+				}
+				costs.breakdown[key] += value
+			}
+		}
+
+		if !costsResponse.HasMore || costsResponse.NextPage == "" {
+			return costs, nil
+		}
+		page = costsResponse.NextPage
 	}
 
-	var total float64
-	for _, bucket := range costsResponse.Data {
-		for _, res := range bucket.Results {
-			valueStr := string(res.Amount.Value)
-			value, parseErr := strconv.ParseFloat(valueStr, 64)
-			if parseErr != nil {
-				log.Error(fmt.Sprintf("error parsing amount value '%s': %s", valueStr, parseErr))
-				continue
+	return openAICosts{}, fmt.Errorf("costs response exceeded %d pages", openAIMaxPages)
+}
+
+func (o OpenAICollector) openAICollectCompletionsUsage(
+	ctx context.Context,
+	apiKey string,
+	startTime, endTime time.Time,
+) (map[string]openAIModelUsage, error) {
+	days := int(endTime.Sub(startTime)/(24*time.Hour)) + 1
+
+	query := url.Values{}
+	query.Set("start_time", strconv.FormatInt(startTime.Unix(), 10))
+	query.Set("bucket_width", "1d")
+	query.Set("limit", strconv.Itoa(days))
+	query.Add("group_by", "model")
+
+	usage := make(map[string]openAIModelUsage)
+
+	page := ""
+	for range openAIMaxPages {
+		if page != "" {
+			query.Set("page", page)
+		}
+
+		data, err := o.openAIGet(ctx, apiKey, "/organization/usage/completions", query)
+		if err != nil {
+			return nil, err
+		}
+
+		var usageResponse OpenAICompletionsUsageResponse
+		if err = decodeOpenAIResponse(data, &usageResponse); err != nil {
+			return nil, err
+		}
+
+		for _, bucket := range usageResponse.Data {
+			for _, res := range bucket.Results {
+				model := groupValue(res.Model)
+				u := usage[model]
+				u.inputTokens += float64(res.InputTokens)
+				u.outputTokens += float64(res.OutputTokens)
+				u.inputCachedTokens += float64(res.InputCachedTokens)
+				u.requests += float64(res.NumModelRequests)
+				usage[model] = u
 			}
-			total += value
 		}
+
+		if !usageResponse.HasMore || usageResponse.NextPage == "" {
+			return usage, nil
+		}
+		page = usageResponse.NextPage
+	}
+
+	return nil, fmt.Errorf("completions usage response exceeded %d pages", openAIMaxPages)
+}
+
+func (o OpenAICollector) openAIGet(
+	ctx context.Context,
+	apiKey, path string,
+	query url.Values,
+) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s%s?%s", o.Cfg.OpenAIAPIURL, path, query.Encode())
+
+	return http.GetRequest(ctx, reqURL, apiKey, o.Cfg.HTTPTimeout)
+}
+
+func decodeOpenAIResponse(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
 	}
 
-	return total, nil
+	return nil
 }
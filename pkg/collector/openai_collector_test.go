@@ -0,0 +1,171 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+const testOpenAIKey = "sk-admin-test"
+
+// newOpenAIServer serves the OpenAI admin API fixtures from testdata and fails
+// the test on requests that are not authenticated or grouped like the
+// collector expects.
+func newOpenAIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testOpenAIKey {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+
+		var (
+			fixture string
+			groupBy []string
+		)
+		switch r.URL.Path {
+		case "/organization/costs":
+			groupBy = []string{"project_id", "line_item"}
+			fixture = "openai_costs_page1.json"
+			if r.URL.Query().Get("page") == "page_2" {
+				fixture = "openai_costs_page2.json"
+			}
+		case "/organization/usage/completions":
+			groupBy = []string{"model"}
+			fixture = "openai_completions_usage.json"
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		if got := r.URL.Query()["group_by"]; !slices.Equal(got, groupBy) {
+			t.Errorf("%s group_by = %v, want %v", r.URL.Path, got, groupBy)
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Errorf("error reading fixture: %s", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+func newTestOpenAICollector(serverURL string) OpenAICollector {
+	return OpenAICollector{
+		Cfg: config.Config{
+			OpenAIAPIKey: "prod:" + testOpenAIKey,
+			OpenAIAPIURL: serverURL,
+			HTTPTimeout:  5,
+			Timeout:      5,
+		},
+	}
+}
+
+// TestOpenAICollectCosts tests that cost pages are followed and amounts are
+// grouped by project and line item.
+func TestOpenAICollectCosts(t *testing.T) {
+	server := newOpenAIServer(t)
+	defer server.Close()
+
+	o := newTestOpenAICollector(server.URL)
+	costs, err := o.openAICollectCosts(t.Context(), testOpenAIKey, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("openAICollectCosts() error = %s", err)
+	}
+
+	if costs.total != 22.25 {
+		t.Errorf("total = %v, want 22.25", costs.total)
+	}
+
+	input := openAICostKey{projectID: "proj_agents", lineItem: "gpt-4o, input", currency: "USD"}
+	if costs.breakdown[input] != 20 {
+		t.Errorf("breakdown[%v] = %v, want 20", input, costs.breakdown[input])
+	}
+
+	ungrouped := openAICostKey{projectID: unknownGroup, lineItem: unknownGroup, currency: "USD"}
+	if costs.breakdown[ungrouped] != 2.25 {
+		t.Errorf("breakdown[%v] = %v, want 2.25", ungrouped, costs.breakdown[ungrouped])
+	}
+}
+
+// TestOpenAICollectCompletionsUsage tests that completions usage is summed per
+// model over all buckets.
+func TestOpenAICollectCompletionsUsage(t *testing.T) {
+	server := newOpenAIServer(t)
+	defer server.Close()
+
+	o := newTestOpenAICollector(server.URL)
+	usage, err := o.openAICollectCompletionsUsage(t.Context(), testOpenAIKey, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("openAICollectCompletionsUsage() error = %s", err)
+	}
+
+	gpt := usage["gpt-4o-2024-08-06"]
+	expected := openAIModelUsage{inputTokens: 1500, outputTokens: 500, inputCachedTokens: 200, requests: 15}
+	if gpt != expected {
+		t.Errorf("gpt-4o usage = %+v, want %+v", gpt, expected)
+	}
+
+	if usage[unknownGroup].requests != 1 {
+		t.Errorf("unknown model requests = %v, want 1", usage[unknownGroup].requests)
+	}
+}
+
+// TestOpenAICollectCostsPageCap tests that paging stops with an error after
+// openAIMaxPages pages.
+func TestOpenAICollectCostsPageCap(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"data": [], "has_more": true, "next_page": "next"}`))
+	}))
+	defer server.Close()
+
+	o := newTestOpenAICollector(server.URL)
+	_, err := o.openAICollectCosts(t.Context(), testOpenAIKey, time.Now(), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "exceeded") {
+		t.Errorf("openAICollectCosts() error = %v, want page limit error", err)
+	}
+
+	if requests.Load() != openAIMaxPages {
+		t.Errorf("server received %d requests, want %d", requests.Load(), openAIMaxPages)
+	}
+}
+
+// TestOpenAICollect tests that a full scrape emits the cost total, the cost
+// breakdown and the completions usage series.
+func TestOpenAICollect(t *testing.T) {
+	server := newOpenAIServer(t)
+	defer server.Close()
+
+	o := newTestOpenAICollector(server.URL)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		o.Collect(ch)
+		close(ch)
+	}()
+
+	count := 0
+	for range ch {
+		count++
+	}
+
+	// 1 total + 2 cost breakdown series + 2 models * (3 token types + requests).
+	if count != 11 {
+		t.Errorf("Collect() emitted %d metrics, want 11", count)
+	}
+}
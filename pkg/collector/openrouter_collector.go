@@ -19,7 +19,11 @@ const (
 	openRouterLimitRemainingMetricName = "openrouter_limit_remaining"
 	openRouterCreditsTotalMetricName   = "openrouter_credits_total"
 	openRouterCreditsUsageMetricName   = "openrouter_credits_usage"
-	openRouterAPIURL                   = "https://openrouter.ai/api/v1"
+	openRouterBurnRateMetricName       = "openrouter_credits_burn_rate_per_hour"
+	openRouterDepletionMetricName      = "openrouter_credits_depletion_seconds"
+	openRouterActivityRequestsName     = "openrouter_activity_requests"
+	openRouterActivityTokensName       = "openrouter_activity_tokens"
+	openRouterActivitySpendName        = "openrouter_activity_spend"
 )
 
 type OpenRouterKeyResponse struct {
@@ -42,6 +46,19 @@ type OpenRouterCreditsResponse struct {
 	} `json:"data"`
 }
 
+type OpenRouterActivityResponse struct {
+	Data []struct {
+		Date             string  `json:"date"`
+		Model            string  `json:"model"`
+		ProviderName     string  `json:"provider_name"`
+		Usage            float64 `json:"usage"`
+		Requests         float64 `json:"requests"`
+		PromptTokens     float64 `json:"prompt_tokens"`
+		CompletionTokens float64 `json:"completion_tokens"`
+		ReasoningTokens  float64 `json:"reasoning_tokens"`
+	} `json:"data"`
+}
+
 //nolint:gochecknoglobals // this is needed as it's used in multiple places
 var (
 	openRouterUsage = prometheus.NewDesc(
@@ -101,10 +118,71 @@ var (
 		},
 		nil,
 	)
+
+	openRouterBurnRate = prometheus.NewDesc(
+		openRouterBurnRateMetricName,
+		"Returns OpenRouter remaining credits spent per hour over the given window",
+		[]string{
+			"key",
+			"unit",
+			"window",
+		},
+		nil,
+	)
+
+	openRouterDepletion = prometheus.NewDesc(
+		openRouterDepletionMetricName,
+		"Returns estimated seconds until OpenRouter credits are depleted at the burn rate of the given window",
+		[]string{
+			"key",
+			"unit",
+			"window",
+		},
+		nil,
+	)
+
+	openRouterActivityRequests = prometheus.NewDesc(
+		openRouterActivityRequestsName,
+		"Returns OpenRouter request count by model and provider for the last complete UTC day",
+		[]string{
+			"key",
+			"model",
+			"provider",
+			"status",
+		},
+		nil,
+	)
+
+	openRouterActivityTokens = prometheus.NewDesc(
+		openRouterActivityTokensName,
+		"Returns OpenRouter token count by model, provider and token type for the last complete UTC day",
+		[]string{
+			"key",
+			"model",
+			"provider",
+			"type",
+			"status",
+		},
+		nil,
+	)
+
+	openRouterActivitySpend = prometheus.NewDesc(
+		openRouterActivitySpendName,
+		"Returns OpenRouter spend in USD by model and provider for the last complete UTC day",
+		[]string{
+			"key",
+			"model",
+			"provider",
+			"unit",
+			"status",
+		},
+		nil,
+	)
 )
 
 type OpenRouterCollector struct {
-	Cfg config.Config
+	Cfg     config.Config
+	History *BalanceHistory
 }
 
 func (c OpenRouterCollector) Describe(ch chan<- *prometheus.Desc) {
@@ -113,33 +191,62 @@ func (c OpenRouterCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- openRouterLimitRemaining
 	ch <- openRouterCreditsTotal
 	ch <- openRouterCreditsUsage
+
+	if c.History != nil {
+		ch <- openRouterBurnRate
+		ch <- openRouterDepletion
+	}
+
+	if c.Cfg.OpenRouterProvisioningKey != "" {
+		ch <- openRouterActivityRequests
+		ch <- openRouterActivityTokens
+		ch <- openRouterActivitySpend
+	}
 }
 
 func (c OpenRouterCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectFailures(ch)
+}
+
+// collectFailures reports the provisioning keys whose activity could not be
+// fetched, which leaves no activity series behind.
+func (c OpenRouterCollector) collectFailures(ch chan<- prometheus.Metric) []string {
 	ctx, cancel := context.WithTimeout(
 		context.Background(),
 		time.Duration(c.Cfg.Timeout)*time.Second,
 	)
 	defer cancel()
 
-	for _, apiKey := range splitCommaList(c.Cfg.OpenRouterAPIKey) {
-		c.collectKey(ctx, ch, apiKey)
+	for _, account := range config.ParseAPIAccounts(c.Cfg.OpenRouterAPIKey) {
+		c.collectKey(ctx, ch, account)
+	}
+
+	var failed []string
+	for _, account := range config.ParseAPIAccounts(c.Cfg.OpenRouterProvisioningKey) {
+		if err := c.collectActivity(ctx, ch, account); err != nil {
+			failed = append(failed, account.Name)
+		}
 	}
+
+	return failed
 }
 
 func (c OpenRouterCollector) collectKey(
 	ctx context.Context,
 	ch chan<- prometheus.Metric,
-	apiKey string,
+	account config.APIAccount,
 ) {
 	keyStatus := successStatus
-	keyResp, err := c.openRouterCollectKey(ctx, apiKey)
+	keyResp, err := c.openRouterCollectKey(ctx, account.Key)
 	keyLabel := keyResp.Data.Label
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting OpenRouter key info %s", err))
 		keyStatus = errorStatus
 		keyResp = OpenRouterKeyResponse{}
-		keyLabel = redactKey(apiKey)
+		keyLabel = account.Name
+	}
+	if account.Aliased {
+		keyLabel = account.Name
 	}
 
 	period := keyResp.Data.LimitReset
@@ -180,7 +287,7 @@ func (c OpenRouterCollector) collectKey(
 	)
 
 	creditsStatus := successStatus
-	creditsResp, err := c.openRouterCollectCredits(ctx, apiKey)
+	creditsResp, err := c.openRouterCollectCredits(ctx, account.Key)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting OpenRouter credits %s", err))
 		creditsStatus = errorStatus
@@ -200,13 +307,113 @@ func (c OpenRouterCollector) collectKey(
 		creditsResp.Data.TotalUsage,
 		[]string{keyLabel, "USD", creditsStatus}...,
 	)
+
+	if creditsStatus == successStatus {
+		remaining := creditsResp.Data.TotalCredits - creditsResp.Data.TotalUsage
+		c.History.collect(ch, openRouterBurnRate, openRouterDepletion, account.Key, remaining, keyLabel, "USD")
+	}
+}
+
+// collectActivity exports per-model and per-provider activity of the last
+// complete UTC day. The activity endpoint requires a provisioning key.
+func (c OpenRouterCollector) collectActivity(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+) error {
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+
+	activity, err := c.openRouterCollectActivity(ctx, account.Key, yesterday)
+	if err != nil {
+		log.Error(fmt.Sprintf("error collecting OpenRouter activity %s", err))
+		return err
+	}
+
+	groups := []usageGroup{}
+	index := map[[2]string]int{}
+	for _, item := range activity.Data {
+		key := [2]string{item.Model, item.ProviderName}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, usageGroup{
+				labels: []string{item.Model, item.ProviderName},
+				values: make([]float64, 5),
+			})
+		}
+
+		groups[i].values[0] += item.Usage
+		groups[i].values[1] += item.Requests
+		groups[i].values[2] += item.PromptTokens
+		groups[i].values[3] += item.CompletionTokens
+		groups[i].values[4] += item.ReasoningTokens
+	}
+
+	for _, g := range capUsageGroups(groups, c.Cfg.OpenRouterActivityMaxSeries) {
+		model, provider := g.labels[0], g.labels[1]
+
+		ch <- prometheus.MustNewConstMetric(
+			openRouterActivitySpend,
+			prometheus.GaugeValue,
+			g.values[0],
+			[]string{account.Name, model, provider, "USD", successStatus}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			openRouterActivityRequests,
+			prometheus.GaugeValue,
+			g.values[1],
+			[]string{account.Name, model, provider, successStatus}...,
+		)
+
+		tokenTypes := []struct {
+			tokenType string
+			value     float64
+		}{
+			{"prompt", g.values[2]},
+			{"completion", g.values[3]},
+			{"reasoning", g.values[4]},
+		}
+		for _, t := range tokenTypes {
+			ch <- prometheus.MustNewConstMetric(
+				openRouterActivityTokens,
+				prometheus.GaugeValue,
+				t.value,
+				[]string{account.Name, model, provider, t.tokenType, successStatus}...,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (c OpenRouterCollector) openRouterCollectActivity(
+	ctx context.Context,
+	apiKey, date string,
+) (OpenRouterActivityResponse, error) {
+	url := fmt.Sprintf("%s/activity?date=%s", c.Cfg.OpenRouterAPIURL, date)
+
+	data, err := http.GetRequest(ctx, url, apiKey, c.Cfg.HTTPTimeout)
+	if err != nil {
+		return OpenRouterActivityResponse{}, err
+	}
+
+	var resp OpenRouterActivityResponse
+	if err = json.Unmarshal(data, &resp); err != nil {
+		return OpenRouterActivityResponse{}, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	log.Info("OpenRouter API activity request successful")
+
+	return resp, nil
 }
 
 func (c OpenRouterCollector) openRouterCollectKey(
 	ctx context.Context,
 	apiKey string,
 ) (OpenRouterKeyResponse, error) {
-	url := fmt.Sprintf("%s/auth/key", openRouterAPIURL)
+	url := fmt.Sprintf("%s/auth/key", c.Cfg.OpenRouterAPIURL)
 
 	data, err := http.GetRequest(ctx, url, apiKey, c.Cfg.HTTPTimeout)
 	if err != nil {
@@ -227,7 +434,7 @@ func (c OpenRouterCollector) openRouterCollectCredits(
 	ctx context.Context,
 	apiKey string,
 ) (OpenRouterCreditsResponse, error) {
-	url := fmt.Sprintf("%s/credits", openRouterAPIURL)
+	url := fmt.Sprintf("%s/credits", c.Cfg.OpenRouterAPIURL)
 
 	data, err := http.GetRequest(ctx, url, apiKey, c.Cfg.HTTPTimeout)
 	if err != nil {
@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+const testOpenRouterProvisioningKey = "sk-or-v1-provisioning-test"
+
+// newOpenRouterServer serves the OpenRouter activity fixture from testdata and
+// fails the test on requests that do not use the provisioning key or do not
+// ask for the last complete UTC day.
+func newOpenRouterServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/activity" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+testOpenRouterProvisioningKey {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+
+		yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+		if r.URL.Query().Get("date") != yesterday {
+			t.Errorf("date = %q, want %q", r.URL.Query().Get("date"), yesterday)
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", "openrouter_activity.json"))
+		if err != nil {
+			t.Errorf("error reading fixture: %s", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+// TestOpenRouterCollectActivity tests that activity is summed per model and
+// provider and that groups beyond OPENROUTER_ACTIVITY_MAX_SERIES are folded
+// into "other".
+func TestOpenRouterCollectActivity(t *testing.T) {
+	server := newOpenRouterServer(t)
+	defer server.Close()
+
+	c := OpenRouterCollector{
+		Cfg: config.Config{
+			OpenRouterProvisioningKey:   "ops:" + testOpenRouterProvisioningKey,
+			OpenRouterActivityMaxSeries: 2,
+			OpenRouterAPIURL:            server.URL,
+			HTTPTimeout:                 5,
+			Timeout:                     5,
+		},
+	}
+
+	metrics := collectGauges(t, c)
+
+	spend := map[[2]string]float64{}
+	for _, m := range metrics[openRouterActivitySpend] {
+		labels := metricLabels(m)
+		if labels["key"] != "ops" || labels["status"] != successStatus {
+			t.Errorf("unexpected spend labels %v", labels)
+		}
+		spend[[2]string{labels["model"], labels["provider"]}] = m.GetGauge().GetValue()
+	}
+
+	expected := map[[2]string]float64{
+		{"anthropic/claude-sonnet-4", "Anthropic"}: 6,
+		{"openai/gpt-4o", "OpenAI"}:                2,
+		{otherGroup, otherGroup}:                   0.75,
+	}
+	if len(spend) != len(expected) {
+		t.Fatalf("spend series = %v, want %v", spend, expected)
+	}
+	for group, value := range expected {
+		if spend[group] != value {
+			t.Errorf("spend%v = %v, want %v", group, spend[group], value)
+		}
+	}
+
+	requests := map[string]float64{}
+	for _, m := range metrics[openRouterActivityRequests] {
+		requests[metricLabels(m)["model"]] = m.GetGauge().GetValue()
+	}
+	if requests["anthropic/claude-sonnet-4"] != 40 || requests[otherGroup] != 10 {
+		t.Errorf("requests = %v, want 40 for claude-sonnet-4 and 10 for other", requests)
+	}
+
+	// 3 groups * 3 token types.
+	if len(metrics[openRouterActivityTokens]) != 9 {
+		t.Errorf("got %d token series, want 9", len(metrics[openRouterActivityTokens]))
+	}
+}
@@ -0,0 +1,375 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+	validator "github.com/warden-protocol/warden-exporter/pkg/validator"
+)
+
+const (
+	oraclePairsMetricName              = "oracle_currency_pairs"
+	oraclePriceMetricName              = "oracle_currency_pair_price"
+	oracleDecimalsMetricName           = "oracle_currency_pair_decimals"
+	oracleLastUpdateHeightMetricName   = "oracle_currency_pair_last_update_height"
+	oracleStalenessBlocksMetricName    = "oracle_currency_pair_staleness_blocks"
+	oracleStalenessSecondsMetricName   = "oracle_currency_pair_staleness_seconds"
+	oracleVoteWindowBlocksMetricName   = "oracle_vote_window_blocks"
+	oracleValidatorSignedMetricName    = "oracle_validator_signed_blocks"
+	oracleValidatorPriceVotesName      = "oracle_validator_price_votes"
+	oracleValidatorParticipationMetric = "oracle_validator_vote_participation_ratio"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	oraclePairs = prometheus.NewDesc(
+		oraclePairsMetricName,
+		"Returns the number of oracle currency pairs",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	oraclePrice = prometheus.NewDesc(
+		oraclePriceMetricName,
+		"Returns the latest oracle price of the currency pair, adjusted by its decimals",
+		[]string{
+			"chain_id",
+			"pair",
+			"status",
+		},
+		nil,
+	)
+
+	oracleDecimals = prometheus.NewDesc(
+		oracleDecimalsMetricName,
+		"Returns the number of decimals of the oracle price of the currency pair",
+		[]string{
+			"chain_id",
+			"pair",
+			"status",
+		},
+		nil,
+	)
+
+	oracleLastUpdateHeight = prometheus.NewDesc(
+		oracleLastUpdateHeightMetricName,
+		"Returns the block height of the last oracle price update of the currency pair",
+		[]string{
+			"chain_id",
+			"pair",
+			"status",
+		},
+		nil,
+	)
+
+	oracleStalenessBlocks = prometheus.NewDesc(
+		oracleStalenessBlocksMetricName,
+		"Returns the number of blocks since the last oracle price update of the currency pair",
+		[]string{
+			"chain_id",
+			"pair",
+			"status",
+		},
+		nil,
+	)
+
+	oracleStalenessSeconds = prometheus.NewDesc(
+		oracleStalenessSecondsMetricName,
+		"Returns the number of seconds since the last oracle price update of the currency pair",
+		[]string{
+			"chain_id",
+			"pair",
+			"status",
+		},
+		nil,
+	)
+
+	oracleVoteWindowBlocks = prometheus.NewDesc(
+		oracleVoteWindowBlocksMetricName,
+		"Returns the number of blocks with oracle vote extensions in the recent window",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	oracleValidatorSigned = prometheus.NewDesc(
+		oracleValidatorSignedMetricName,
+		"Returns the number of blocks with oracle vote extensions the validator signed in the recent window",
+		[]string{
+			"chain_id",
+			"valcons",
+			"valoper",
+			"moniker",
+			"status",
+		},
+		nil,
+	)
+
+	oracleValidatorPriceVotes = prometheus.NewDesc(
+		oracleValidatorPriceVotesName,
+		"Returns the number of blocks in the recent window the validator vote extension contained prices",
+		[]string{
+			"chain_id",
+			"valcons",
+			"valoper",
+			"moniker",
+			"status",
+		},
+		nil,
+	)
+
+	oracleValidatorParticipation = prometheus.NewDesc(
+		oracleValidatorParticipationMetric,
+		"Returns the share of blocks with oracle vote extensions in the recent window the validator voted prices "+
+			"in, a validator whose oracle sidecar is down keeps signing but stops voting prices",
+		[]string{
+			"chain_id",
+			"valcons",
+			"valoper",
+			"moniker",
+			"status",
+		},
+		nil,
+	)
+)
+
+type OracleCollector struct {
+	Cfg config.Config
+}
+
+func (o OracleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- oraclePairs
+	ch <- oraclePrice
+	ch <- oracleDecimals
+	ch <- oracleLastUpdateHeight
+	ch <- oracleStalenessBlocks
+	ch <- oracleStalenessSeconds
+	ch <- oracleVoteWindowBlocks
+	ch <- oracleValidatorSigned
+	ch <- oracleValidatorPriceVotes
+	ch <- oracleValidatorParticipation
+}
+
+func (o OracleCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(o.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	var errors []string
+
+	errors = o.collectPriceMetrics(ctx, ch, errors)
+	errors = o.collectVoteMetrics(ctx, ch, errors)
+
+	if len(errors) > 0 {
+		log.Info(fmt.Sprintf("Oracle metrics collection completed with errors: %v", errors))
+	} else {
+		log.Info("Oracle metrics collection completed successfully")
+	}
+}
+
+func (o OracleCollector) collectPriceMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	status := successStatus
+
+	prices, err := o.oraclePrices(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting oracle prices: %s", err))
+		errors = append(errors, "prices")
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		oraclePairs,
+		prometheus.GaugeValue,
+		float64(len(prices)),
+		[]string{o.Cfg.ChainID, status}...,
+	)
+
+	if len(prices) == 0 {
+		return errors
+	}
+
+	heightStatus := successStatus
+	latestHeight, err := grpc.LatestBlockHeight(ctx, o.Cfg)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting latest block height: %s", err))
+		errors = append(errors, "latest height")
+		heightStatus = errorStatus
+	}
+
+	for _, price := range prices {
+		ch <- prometheus.MustNewConstMetric(
+			oracleDecimals,
+			prometheus.GaugeValue,
+			float64(price.Decimals),
+			[]string{o.Cfg.ChainID, price.Pair, status}...,
+		)
+
+		// The pair was added but no price has been voted yet
+		if price.Price == nil {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			oraclePrice,
+			prometheus.GaugeValue,
+			denomAmount(*price.Price, int(price.Decimals)),
+			[]string{o.Cfg.ChainID, price.Pair, status}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			oracleLastUpdateHeight,
+			prometheus.GaugeValue,
+			float64(price.BlockHeight),
+			[]string{o.Cfg.ChainID, price.Pair, status}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			oracleStalenessSeconds,
+			prometheus.GaugeValue,
+			time.Since(price.BlockTime).Seconds(),
+			[]string{o.Cfg.ChainID, price.Pair, status}...,
+		)
+
+		var stalenessBlocks int64
+		if latestHeight > 0 {
+			stalenessBlocks = max(latestHeight-int64(price.BlockHeight), 0)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			oracleStalenessBlocks,
+			prometheus.GaugeValue,
+			float64(stalenessBlocks),
+			[]string{o.Cfg.ChainID, price.Pair, heightStatus}...,
+		)
+	}
+
+	return errors
+}
+
+func (o OracleCollector) oraclePrices(ctx context.Context) ([]grpc.OraclePrice, error) {
+	client, err := grpc.NewClient(o.Cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if tempErr := client.CloseConn(); tempErr != nil {
+			log.Error(tempErr.Error())
+		}
+	}()
+
+	return client.OraclePrices(ctx)
+}
+
+func (o OracleCollector) collectVoteMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	errors []string,
+) []string {
+	status := successStatus
+
+	blocks, err := grpc.RecentBlocks(ctx, o.Cfg, o.Cfg.OracleBlockWindow)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting oracle votes: %s", err))
+		errors = append(errors, "votes")
+		status = errorStatus
+	}
+
+	votes := grpc.RecentOracleVotes(blocks, o.Cfg.OracleBlockWindow)
+	if err == nil && votes.MissingBlocks > 0 {
+		// Votes in the missing blocks are not counted, so the participation
+		// of every validator is too low
+		log.Error(
+			fmt.Sprintf(
+				"error getting oracle votes: %d of the last %d blocks could not be fetched",
+				votes.MissingBlocks,
+				o.Cfg.OracleBlockWindow,
+			),
+		)
+		errors = append(errors, "missing blocks")
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		oracleVoteWindowBlocks,
+		prometheus.GaugeValue,
+		float64(votes.Blocks),
+		[]string{o.Cfg.ChainID, status}...,
+	)
+
+	if len(votes.Votes) == 0 {
+		return errors
+	}
+
+	// Validators are only used to label votes with their operator and
+	// moniker, votes are still exported without them
+	vals, err := grpc.SigningValidators(ctx, o.Cfg)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting signing validators: %s", err))
+		errors = append(errors, "validators")
+	}
+
+	byConsAddress := make(map[string]validator.Validator, len(vals))
+	for _, val := range vals {
+		byConsAddress[val.ConsAddress] = val
+	}
+
+	for valcons, v := range votes.Votes {
+		valoper, moniker := unknownGroup, unknownGroup
+		if val, ok := byConsAddress[valcons]; ok {
+			valoper, moniker = val.OperatorAddress, val.Moniker
+		}
+
+		labels := []string{o.Cfg.ChainID, valcons, valoper, moniker, status}
+
+		ch <- prometheus.MustNewConstMetric(
+			oracleValidatorSigned,
+			prometheus.GaugeValue,
+			float64(v.Signed),
+			labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			oracleValidatorPriceVotes,
+			prometheus.GaugeValue,
+			float64(v.WithPrices),
+			labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			oracleValidatorParticipation,
+			prometheus.GaugeValue,
+			participationRatio(v.WithPrices, votes.Blocks),
+			labels...,
+		)
+	}
+
+	return errors
+}
+
+// participationRatio returns the share of blocks a validator voted prices in,
+// 0 when the window has no blocks with oracle data.
+func participationRatio(priceVotes, blocks int) float64 {
+	if blocks == 0 {
+		return 0
+	}
+
+	return float64(priceVotes) / float64(blocks)
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	base "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	cmttypes "cosmossdk.io/api/tendermint/types"
+	"github.com/prometheus/client_golang/prometheus"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// blocksServer serves the blocks up to latestHeight, except the ones in
+// missing.
+type blocksServer struct {
+	base.UnimplementedServiceServer
+	latestHeight int64
+	missing      map[int64]bool
+}
+
+func (s *blocksServer) block(height int64) *cmttypes.Block {
+	return &cmttypes.Block{
+		Header: &cmttypes.Header{Height: height, Time: timestamppb.Now()},
+		Data:   &cmttypes.Data{},
+	}
+}
+
+func (s *blocksServer) GetLatestBlock(
+	_ context.Context,
+	_ *base.GetLatestBlockRequest,
+) (*base.GetLatestBlockResponse, error) {
+	return &base.GetLatestBlockResponse{Block: s.block(s.latestHeight)}, nil
+}
+
+func (s *blocksServer) GetBlockByHeight(
+	_ context.Context,
+	req *base.GetBlockByHeightRequest,
+) (*base.GetBlockByHeightResponse, error) {
+	if req.GetHeight() > s.latestHeight || s.missing[req.GetHeight()] {
+		return nil, status.Error(codes.NotFound, "block not found")
+	}
+
+	return &base.GetBlockByHeightResponse{Block: s.block(req.GetHeight())}, nil
+}
+
+// TestOracleCollectErrors tests that failing oracle queries and blocks
+// missing from the vote window are exported with the error status.
+func TestOracleCollectErrors(t *testing.T) {
+	cfg := newTestServer(t, func(server *googlegrpc.Server) {
+		base.RegisterServiceServer(server, &blocksServer{latestHeight: 20, missing: map[int64]bool{18: true}})
+	})
+	cfg.OracleBlockWindow = 5
+
+	metrics := collectGauges(t, OracleCollector{Cfg: cfg})
+
+	for _, desc := range []*prometheus.Desc{oraclePairs, oracleVoteWindowBlocks} {
+		if len(metrics[desc]) != 1 {
+			t.Fatalf("got %d %s series, want 1", len(metrics[desc]), desc)
+		}
+
+		m := metrics[desc][0]
+		if metricLabels(m)["status"] != errorStatus || m.GetGauge().GetValue() != 0 {
+			t.Errorf("%s = %v with labels %v, want 0 with the error status",
+				desc, m.GetGauge().GetValue(), metricLabels(m))
+		}
+	}
+}
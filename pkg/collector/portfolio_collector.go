@@ -0,0 +1,232 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	portfolioBalanceMetricName    = "portfolio_balance"
+	portfolioBalanceUSDMetricName = "portfolio_balance_usd"
+	portfolioWalletsMetricName    = "portfolio_wallets"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	portfolioBalance = prometheus.NewDesc(
+		portfolioBalanceMetricName,
+		"Returns the total native balance of the wallets in a group by chain and symbol",
+		[]string{
+			"group",
+			"chain",
+			"symbol",
+			"status",
+		},
+		nil,
+	)
+
+	portfolioBalanceUSD = prometheus.NewDesc(
+		portfolioBalanceUSDMetricName,
+		"Returns the total USD value of the wallets in a group across all chains",
+		[]string{
+			"group",
+			"status",
+		},
+		nil,
+	)
+
+	portfolioWallets = prometheus.NewDesc(
+		portfolioWalletsMetricName,
+		"Returns the number of wallets in a group by chain",
+		[]string{
+			"group",
+			"chain",
+		},
+		nil,
+	)
+)
+
+type portfolioKey struct {
+	chain  string
+	symbol string
+}
+
+type portfolioTotal struct {
+	balance float64
+	wallets int
+	failed  bool
+}
+
+// PortfolioCollector aggregates the balances of the wallets in WALLET_GROUPS
+// into per-group totals, in native units and, with a price feed, in USD.
+type PortfolioCollector struct {
+	Cfg    config.Config
+	Groups []config.WalletGroup
+	Prices *PriceFeed
+}
+
+func (p PortfolioCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- portfolioBalance
+	ch <- portfolioWallets
+	if p.Prices != nil {
+		ch <- portfolioBalanceUSD
+	}
+}
+
+func (p PortfolioCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(p.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	balances := p.walletBalances(ctx)
+
+	for _, group := range p.Groups {
+		p.collectGroup(ctx, ch, group, balances)
+	}
+}
+
+func (p PortfolioCollector) collectGroup(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	group config.WalletGroup,
+	balances map[config.GroupWallet]float64,
+) {
+	keys := []portfolioKey{}
+	totals := map[portfolioKey]*portfolioTotal{}
+	usd := 0.0
+	usdStatus := successStatus
+
+	for _, wallet := range group.Wallets {
+		key := portfolioKey{chain: wallet.Chain, symbol: p.chainSymbol(wallet.Chain)}
+		total, ok := totals[key]
+		if !ok {
+			total = &portfolioTotal{}
+			totals[key] = total
+			keys = append(keys, key)
+		}
+		total.wallets++
+
+		balance, ok := balances[wallet]
+		if !ok {
+			total.failed = true
+			usdStatus = errorStatus
+			continue
+		}
+		total.balance += balance
+
+		value, ok := p.Prices.usdValue(ctx, key.symbol, balance)
+		if !ok {
+			usdStatus = errorStatus
+			continue
+		}
+		usd += value
+	}
+
+	for _, key := range keys {
+		total := totals[key]
+		status := successStatus
+		if total.failed {
+			status = errorStatus
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			portfolioBalance,
+			prometheus.GaugeValue,
+			total.balance,
+			[]string{group.Name, key.chain, key.symbol, status}...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			portfolioWallets,
+			prometheus.GaugeValue,
+			float64(total.wallets),
+			[]string{group.Name, key.chain}...,
+		)
+	}
+
+	if p.Prices != nil {
+		ch <- prometheus.MustNewConstMetric(
+			portfolioBalanceUSD,
+			prometheus.GaugeValue,
+			usd,
+			[]string{group.Name, usdStatus}...,
+		)
+	}
+}
+
+// walletBalances fetches the balance of every wallet once, even when it is in
+// several groups. Wallets whose balance could not be fetched are left out.
+func (p PortfolioCollector) walletBalances(ctx context.Context) map[config.GroupWallet]float64 {
+	balances := map[config.GroupWallet]float64{}
+
+	var client *grpc.Client
+	defer func() {
+		if client == nil {
+			return
+		}
+		if err := client.CloseConn(); err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	for _, group := range p.Groups {
+		for _, wallet := range group.Wallets {
+			if _, ok := balances[wallet]; ok {
+				continue
+			}
+
+			var (
+				balance float64
+				err     error
+			)
+
+			switch wallet.Chain {
+			case config.CosmosChain:
+				if client == nil {
+					c, clientErr := grpc.NewClient(p.Cfg)
+					if clientErr != nil {
+						log.Error(fmt.Sprintf("error getting portfolio wallet balances: %s", clientErr))
+						continue
+					}
+					client = &c
+				}
+
+				rawBalance, balanceErr := client.Balance(ctx, wallet.Address, p.Cfg.Denom)
+				balance, err = denomAmount(rawBalance, p.Cfg.Exponent), balanceErr
+			case config.BaseChain:
+				balance, err = getBalance(ctx, p.Cfg.BaseRPCURL, wallet.Address, p.Cfg.Timeout)
+			case config.BnbChain:
+				balance, err = getBalance(ctx, p.Cfg.BnbRPCURL, wallet.Address, p.Cfg.Timeout)
+			}
+
+			if err != nil {
+				log.Error(fmt.Sprintf("error getting %s balance for address %s: %s", wallet.Chain, wallet.Address, err))
+				continue
+			}
+
+			balances[wallet] = balance
+		}
+	}
+
+	return balances
+}
+
+func (p PortfolioCollector) chainSymbol(chain string) string {
+	switch chain {
+	case config.BaseChain:
+		return "ETH"
+	case config.BnbChain:
+		return "BNB"
+	default:
+		return p.Cfg.Symbol
+	}
+}
@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+// newBalanceServer serves eth_getBalance with the given wei balances in hex
+// and an RPC error for unknown addresses.
+func newBalanceServer(t *testing.T, balances map[string]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("error decoding request: %s", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		balance, ok := balances[fmt.Sprint(req.Params[0])]
+		if !ok {
+			_, _ = fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"unknown account"}}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"%s"}`, balance)
+	}))
+}
+
+// TestPortfolioCollect tests that balances are summed per group and chain,
+// valued in USD, and that a failing wallet marks its totals as errors.
+func TestPortfolioCollect(t *testing.T) {
+	rpc := newBalanceServer(t, map[string]string{
+		"0xa": "0xde0b6b3a7640000",  // 1
+		"0xb": "0x1bc16d674ec80000", // 2
+	})
+	defer rpc.Close()
+
+	prices := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ethereum":{"usd":2000},"binancecoin":{"usd":500}}`)
+	}))
+	defer prices.Close()
+
+	cfg := config.Config{
+		BaseRPCURL:         rpc.URL,
+		BnbRPCURL:          rpc.URL,
+		CoinGeckoSymbolMap: "ETH=ethereum,BNB=binancecoin",
+		CoinGeckoAPIURL:    prices.URL,
+		WalletGroups:       "treasury=base:0xa,base:0xb,bnb:0xa;faucet=base:0xb,bnb:0xc",
+		HTTPTimeout:        5,
+		Timeout:            5,
+		TTL:                60,
+	}
+
+	groups, err := cfg.ParseWalletGroups()
+	if err != nil {
+		t.Fatalf("ParseWalletGroups() error = %s", err)
+	}
+
+	feed := NewPriceFeed(cfg)
+	p := PortfolioCollector{Cfg: cfg, Groups: groups, Prices: feed}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		p.Collect(ch)
+		close(ch)
+	}()
+
+	// Series are keyed by metric name followed by their label values, in
+	// label name order.
+	got := map[string]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err = m.Write(&metric); err != nil {
+			t.Fatalf("error writing metric: %s", err)
+		}
+
+		var key string
+		switch m.Desc() {
+		case portfolioBalance:
+			key = portfolioBalanceMetricName
+		case portfolioBalanceUSD:
+			key = portfolioBalanceUSDMetricName
+		default:
+			continue
+		}
+		for _, label := range metric.GetLabel() {
+			key += "," + label.GetValue()
+		}
+		got[key] = metric.GetGauge().GetValue()
+	}
+
+	expected := map[string]float64{
+		"portfolio_balance,base,treasury,success,ETH": 3,
+		"portfolio_balance,bnb,treasury,success,BNB":  1,
+		"portfolio_balance,base,faucet,success,ETH":   2,
+		"portfolio_balance,bnb,faucet,error,BNB":      0,
+		"portfolio_balance_usd,treasury,success":      6500,
+		"portfolio_balance_usd,faucet,error":          4000,
+	}
+
+	for key, want := range expected {
+		if value, ok := got[key]; !ok || value != want {
+			t.Errorf("%s = %v (found %v), want %v", key, value, ok, want)
+		}
+	}
+}
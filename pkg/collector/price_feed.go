@@ -0,0 +1,184 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+// PriceFeed fetches USD prices of the coins in COINGECKO_SYMBOL_MAP and caches
+// them for TTL seconds, so the CoinGecko collector and every balance
+// collector share one simple/price request per TTL.
+type PriceFeed struct {
+	cfg     config.Config
+	symbols map[string]string
+
+	mu        sync.Mutex
+	prices    map[string]float64
+	fetchedAt time.Time
+	failedAt  time.Time
+	lastErr   error
+}
+
+func NewPriceFeed(cfg config.Config) *PriceFeed {
+	return &PriceFeed{
+		cfg:     cfg,
+		symbols: cfg.CoinGeckoSymbols(),
+	}
+}
+
+// coinIDs returns the configured coin ids, de-duplicated and sorted.
+func (p *PriceFeed) coinIDs() []string {
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, id := range p.symbols {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Prices returns the USD price of every configured coin id, refreshing the
+// cache when it is older than TTL. A failed refresh is not retried for TTL
+// seconds, so an outage does not block every caller on a request. Until then
+// the error is returned together with the last fetched prices, if any.
+func (p *PriceFeed) Prices(ctx context.Context) (map[string]float64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ttl := time.Duration(p.cfg.TTL) * time.Second
+	if p.prices != nil && time.Since(p.fetchedAt) < ttl {
+		return p.prices, nil
+	}
+	if p.lastErr != nil && time.Since(p.failedAt) < ttl {
+		return p.prices, p.lastErr
+	}
+
+	prices, err := p.fetchPrices(ctx)
+	if err != nil {
+		p.failedAt = time.Now()
+		p.lastErr = err
+
+		return p.prices, err
+	}
+
+	p.prices = prices
+	p.fetchedAt = time.Now()
+	p.lastErr = nil
+
+	return prices, nil
+}
+
+// usdValue converts amount of symbol to USD. The second return value is false
+// when the symbol is not mapped to a coin id or its price is unavailable.
+func (p *PriceFeed) usdValue(ctx context.Context, symbol string, amount float64) (float64, bool) {
+	if p == nil {
+		return 0, false
+	}
+
+	coinID, ok := p.symbols[strings.ToUpper(symbol)]
+	if !ok {
+		return 0, false
+	}
+
+	// Values are still converted with the last fetched prices during an
+	// outage
+	prices, err := p.Prices(ctx)
+	if err != nil && prices == nil {
+		log.Error(fmt.Sprintf("error getting CoinGecko prices: %s", err))
+		return 0, false
+	}
+
+	price, ok := prices[coinID]
+	if !ok {
+		return 0, false
+	}
+
+	return amount * price, true
+}
+
+// collectUSD emits the USD value of a balance for balance collectors. A nil
+// price feed is a no-op.
+func (p *PriceFeed) collectUSD(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	desc *prometheus.Desc,
+	symbol string,
+	amount float64,
+	labels ...string,
+) {
+	value, ok := p.usdValue(ctx, symbol, amount)
+	if !ok {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		desc,
+		prometheus.GaugeValue,
+		value,
+		labels...,
+	)
+}
+
+func (p *PriceFeed) fetchPrices(ctx context.Context) (map[string]float64, error) {
+	ids := p.coinIDs()
+	if len(ids) == 0 {
+		return map[string]float64{}, nil
+	}
+
+	query := url.Values{}
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("vs_currencies", "usd")
+	reqURL := fmt.Sprintf("%s/simple/price?%s", p.cfg.CoinGeckoAPIURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if accounts := config.ParseAPIAccounts(p.cfg.CoinGeckoAPIKey); len(accounts) > 0 {
+		req.Header.Add("x-cg-pro-api-key", accounts[0].Key)
+	}
+
+	client := &http.Client{Timeout: time.Duration(p.cfg.HTTPTimeout) * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request: %w", err)
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-OK response: %d", resp.StatusCode)
+	}
+
+	var priceResponse map[string]struct {
+		USD float64 `json:"usd"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&priceResponse); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	prices := make(map[string]float64, len(priceResponse))
+	for id, price := range priceResponse {
+		prices[id] = price.USD
+	}
+
+	log.Info("CoinGecko API price request successful")
+
+	return prices, nil
+}
@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+// TestPriceFeedUSDValue tests that balances are valued by symbol, that prices
+// are cached for TTL and that unmapped symbols are not valued.
+func TestPriceFeedUSDValue(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/simple/price" {
+			http.NotFound(w, r)
+			return
+		}
+		if ids := r.URL.Query().Get("ids"); ids != "binancecoin,ethereum" {
+			t.Errorf("ids = %q, want %q", ids, "binancecoin,ethereum")
+		}
+		_, _ = fmt.Fprint(w, `{"ethereum":{"usd":2500},"binancecoin":{"usd":600}}`)
+	}))
+	defer server.Close()
+
+	p := NewPriceFeed(config.Config{
+		CoinGeckoSymbolMap: "ETH=ethereum, bnb=binancecoin",
+		CoinGeckoAPIURL:    server.URL,
+		HTTPTimeout:        5,
+		TTL:                60,
+	})
+
+	tests := []struct {
+		symbol string
+		amount float64
+		want   float64
+		ok     bool
+	}{
+		{"ETH", 2, 5000, true},
+		{"bnb", 0.5, 300, true},
+		{"SOL", 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := p.usdValue(t.Context(), tt.symbol, tt.amount)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("usdValue(%s, %v) = %v, %v, want %v, %v", tt.symbol, tt.amount, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("price endpoint requested %d times, want 1", n)
+	}
+}
+
+// TestPriceFeedNil tests that a nil price feed values nothing.
+func TestPriceFeedNil(t *testing.T) {
+	var p *PriceFeed
+	if _, ok := p.usdValue(t.Context(), "ETH", 1); ok {
+		t.Error("expected a nil price feed to value nothing")
+	}
+}
+
+// TestPriceFeedBackoff tests that a failed fetch is not retried within TTL
+// and that the last fetched prices are served until then.
+func TestPriceFeedBackoff(t *testing.T) {
+	var (
+		requests atomic.Int32
+		failing  atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ethereum":{"usd":2500}}`)
+	}))
+	defer server.Close()
+
+	p := NewPriceFeed(config.Config{
+		CoinGeckoSymbolMap: "ETH=ethereum",
+		CoinGeckoAPIURL:    server.URL,
+		HTTPTimeout:        5,
+		TTL:                60,
+	})
+
+	failing.Store(true)
+	for range 3 {
+		if _, ok := p.usdValue(t.Context(), "ETH", 1); ok {
+			t.Error("expected no value without prices")
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("price endpoint requested %d times during the outage, want 1", n)
+	}
+
+	// Fetch prices once the outage is over, then let them expire while the
+	// endpoint fails again
+	failing.Store(false)
+	p.failedAt = time.Time{}
+	if got, ok := p.usdValue(t.Context(), "ETH", 2); !ok || got != 5000 {
+		t.Fatalf("usdValue() = %v, %v, want 5000, true", got, ok)
+	}
+
+	failing.Store(true)
+	p.fetchedAt = time.Time{}
+	for range 3 {
+		if got, ok := p.usdValue(t.Context(), "ETH", 2); !ok || got != 5000 {
+			t.Errorf("usdValue() = %v, %v, want the last price", got, ok)
+		}
+	}
+
+	if _, err := p.Prices(t.Context()); err == nil {
+		t.Error("expected Prices() to report the failed refresh")
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("price endpoint requested %d times, want 3", n)
+	}
+}
@@ -0,0 +1,137 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var requestLatencyBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 1800, 3600, 21600}
+
+type requestGroup struct {
+	keychainID  uint64
+	requestType string
+}
+
+type pendingRequest struct {
+	firstSeen time.Time
+	// carried is true for requests already pending when the group was first
+	// observed, their real age is unknown so no latency is recorded for them.
+	carried bool
+}
+
+type latencyHistogram struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+// RequestTracker follows the pending key and sign requests of every keychain
+// between scrapes. Warden requests do not record when they were created, so
+// the age of a pending request and its fulfilment latency are measured from
+// the first scrape that saw it pending, with the scrape interval as
+// resolution. It is shared between scrapes, so collectors hold it by pointer.
+type RequestTracker struct {
+	mu        sync.Mutex
+	pending   map[requestGroup]map[uint64]pendingRequest
+	latencies map[requestGroup]*latencyHistogram
+}
+
+func NewRequestTracker() *RequestTracker {
+	return &RequestTracker{
+		pending:   make(map[requestGroup]map[uint64]pendingRequest),
+		latencies: make(map[requestGroup]*latencyHistogram),
+	}
+}
+
+// resolved returns the requests of a group that were pending at the previous
+// scrape and no longer are, i.e. that were fulfilled or rejected since. Carried
+// requests are left out as no latency is recorded for them.
+func (t *RequestTracker) resolved(group requestGroup, pending []uint64) []uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := make(map[uint64]bool, len(pending))
+	for _, id := range pending {
+		current[id] = true
+	}
+
+	ids := []uint64{}
+	for id, request := range t.pending[group] {
+		if !current[id] && !request.carried {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// observe records the currently pending and fulfilled request ids of a group
+// and returns the age in seconds of the oldest pending request.
+func (t *RequestTracker) observe(group requestGroup, pending []uint64, fulfilled []uint64, now time.Time) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, seen := t.pending[group]
+
+	histogram, ok := t.latencies[group]
+	if !ok {
+		histogram = &latencyHistogram{buckets: make(map[float64]uint64, len(requestLatencyBuckets))}
+		t.latencies[group] = histogram
+	}
+
+	for _, id := range fulfilled {
+		request, ok := previous[id]
+		if !ok || request.carried {
+			continue
+		}
+
+		latency := now.Sub(request.firstSeen).Seconds()
+		histogram.count++
+		histogram.sum += latency
+		for _, bucket := range requestLatencyBuckets {
+			if latency <= bucket {
+				histogram.buckets[bucket]++
+			}
+		}
+	}
+
+	// Only requests that are still pending are kept, so rejected requests and
+	// requests pruned from the chain are forgotten.
+	current := make(map[uint64]pendingRequest, len(pending))
+	var oldest float64
+	for _, id := range pending {
+		request, ok := previous[id]
+		if !ok {
+			request = pendingRequest{firstSeen: now, carried: !seen}
+		}
+		current[id] = request
+		oldest = max(oldest, now.Sub(request.firstSeen).Seconds())
+	}
+	t.pending[group] = current
+
+	return oldest
+}
+
+// latency returns the count, sum and cumulative bucket counts of the
+// fulfilment latencies recorded for a group.
+func (t *RequestTracker) latency(group requestGroup) (uint64, float64, map[float64]uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buckets := make(map[float64]uint64, len(requestLatencyBuckets))
+	histogram, ok := t.latencies[group]
+	if !ok {
+		for _, bucket := range requestLatencyBuckets {
+			buckets[bucket] = 0
+		}
+
+		return 0, 0, buckets
+	}
+
+	for _, bucket := range requestLatencyBuckets {
+		buckets[bucket] = histogram.buckets[bucket]
+	}
+
+	return histogram.count, histogram.sum, buckets
+}
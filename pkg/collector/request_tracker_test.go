@@ -0,0 +1,38 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRequestTrackerObserve tests that pending request ages are measured from
+// the first scrape that saw them and that only requests seen pending by the
+// exporter are counted in the latency histogram.
+func TestRequestTrackerObserve(t *testing.T) {
+	tracker := NewRequestTracker()
+	group := requestGroup{keychainID: 1, requestType: signRequestType}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Request 1 was already pending when the exporter started
+	if age := tracker.observe(group, []uint64{1}, nil, start); age != 0 {
+		t.Errorf("expected age 0 on the first scrape, got %v", age)
+	}
+
+	if age := tracker.observe(group, []uint64{1, 2}, nil, start.Add(time.Minute)); age != 60 {
+		t.Errorf("expected age 60, got %v", age)
+	}
+
+	age := tracker.observe(group, []uint64{}, []uint64{1, 2}, start.Add(3*time.Minute))
+	if age != 0 {
+		t.Errorf("expected age 0 without pending requests, got %v", age)
+	}
+
+	count, sum, buckets := tracker.latency(group)
+	if count != 1 || sum != 120 {
+		t.Errorf("expected one latency of 120 seconds, got count %d sum %v", count, sum)
+	}
+
+	if buckets[60] != 0 || buckets[120] != 1 || buckets[3600] != 1 {
+		t.Errorf("unexpected buckets %v", buckets)
+	}
+}
@@ -0,0 +1,251 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// targetLabelNames are the labels identifying a target, i.e. a wallet, an API
+// key or a validator. The value of any of them is looked up in TARGET_LABELS.
+//
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var targetLabelNames = map[string]bool{
+	"account": true,
+	"key":     true,
+	"valoper": true,
+	"valcons": true,
+	"moniker": true,
+	"group":   true,
+	"node":    true,
+	"target":  true,
+}
+
+// targetLabelsCollector adds the static labels configured for a target to
+// every series of that target.
+type targetLabelsCollector struct {
+	prometheus.Collector
+
+	labels map[string]map[string]string
+	// names are the label names set for any target. Every series gets all of
+	// them, with an empty value when its target does not set one, so all
+	// series of a metric share one set of label names.
+	names []string
+
+	mu sync.Mutex
+	// descNames caches the names each descriptor can be wrapped with.
+	descNames map[string][]string
+}
+
+// WithTargetLabels wraps c so that the labels configured in TARGET_LABELS for
+// a target are added to its series as const labels of wrapped descriptors,
+// like prometheus.WrapRegistererWith does for CONST_LABELS. Labels already
+// set on a series are never overridden. c is returned unchanged when no
+// target labels are set.
+func WithTargetLabels(c prometheus.Collector, labels map[string]map[string]string) prometheus.Collector {
+	if len(labels) == 0 {
+		return c
+	}
+
+	seen := map[string]bool{}
+	names := []string{}
+	for _, targetLabels := range labels {
+		for name := range targetLabels {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return &targetLabelsCollector{
+		Collector: c,
+		labels:    labels,
+		names:     names,
+		descNames: map[string][]string{},
+	}
+}
+
+func (t *targetLabelsCollector) Describe(ch chan<- *prometheus.Desc) {
+	descs := make(chan *prometheus.Desc)
+	go func() {
+		t.Collector.Describe(descs)
+		close(descs)
+	}()
+
+	for desc := range descs {
+		names := t.labelNames(desc)
+		if len(names) == 0 {
+			ch <- desc
+			continue
+		}
+
+		// A series without target labels gets empty values
+		sets := map[string]prometheus.Labels{}
+		for _, targetLabels := range append([]map[string]string{{}}, mapValues(t.labels)...) {
+			set := labelSet(names, targetLabels)
+			sets[labelSetKey(names, set)] = set
+		}
+
+		for _, set := range sets {
+			ch <- wrapDesc(desc, set)
+		}
+	}
+}
+
+func (t *targetLabelsCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		t.Collector.Collect(metrics)
+		close(metrics)
+	}()
+
+	for m := range metrics {
+		ch <- t.withLabels(m)
+	}
+}
+
+// withLabels wraps m with the labels of the first of its target labels that
+// has labels configured.
+func (t *targetLabelsCollector) withLabels(m prometheus.Metric) prometheus.Metric {
+	names := t.labelNames(m.Desc())
+	if len(names) == 0 {
+		return m
+	}
+
+	var metric dto.Metric
+	if err := m.Write(&metric); err != nil {
+		// Pass the metric on as is, the registry reports the error.
+		return m
+	}
+
+	targetLabels := map[string]string{}
+	for _, label := range metric.GetLabel() {
+		if labels, ok := t.labels[label.GetValue()]; ok && targetLabelNames[label.GetName()] {
+			targetLabels = labels
+			break
+		}
+	}
+
+	return wrapMetric(m, labelSet(names, targetLabels))
+}
+
+// labelNames returns the target label names desc does not have yet.
+func (t *targetLabelsCollector) labelNames(desc *prometheus.Desc) []string {
+	key := desc.String()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if names, ok := t.descNames[key]; ok {
+		return names
+	}
+
+	names := []string{}
+	for _, name := range t.names {
+		if !hasLabel(desc, name) {
+			names = append(names, name)
+		}
+	}
+	t.descNames[key] = names
+
+	return names
+}
+
+// labelSet returns the value of every name in targetLabels, or an empty value.
+func labelSet(names []string, targetLabels map[string]string) prometheus.Labels {
+	set := prometheus.Labels{}
+	for _, name := range names {
+		set[name] = targetLabels[name]
+	}
+
+	return set
+}
+
+func labelSetKey(names []string, set prometheus.Labels) string {
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		values = append(values, set[name])
+	}
+
+	return strings.Join(values, "\x00")
+}
+
+func mapValues(m map[string]map[string]string) []map[string]string {
+	values := make([]map[string]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// hasLabel reports whether desc has a label called name, as wrapping it with
+// an existing label name yields an invalid descriptor.
+func hasLabel(desc *prometheus.Desc, name string) bool {
+	return prometheus.NewRegistry().Register(descCollector{wrapDesc(desc, prometheus.Labels{name: ""})}) != nil
+}
+
+// wrapDesc returns desc with labels added as const labels.
+func wrapDesc(desc *prometheus.Desc, labels prometheus.Labels) *prometheus.Desc {
+	descs := make(chan *prometheus.Desc, 1)
+	wrapCollector(descCollector{desc}, labels).Describe(descs)
+
+	return <-descs
+}
+
+// wrapMetric returns m with labels added as const labels of its descriptor.
+func wrapMetric(m prometheus.Metric, labels prometheus.Labels) prometheus.Metric {
+	metrics := make(chan prometheus.Metric, 1)
+	wrapCollector(metricCollector{m}, labels).Collect(metrics)
+
+	return <-metrics
+}
+
+// wrapCollector returns the collector prometheus.WrapRegistererWith registers
+// for c, which adds labels to every descriptor and metric of c.
+func wrapCollector(c prometheus.Collector, labels prometheus.Labels) prometheus.Collector {
+	capture := &captureRegisterer{}
+	_ = prometheus.WrapRegistererWith(labels, capture).Register(c)
+
+	return capture.collector
+}
+
+// captureRegisterer keeps the collector registered with it instead of
+// registering it.
+type captureRegisterer struct {
+	prometheus.Registerer
+
+	collector prometheus.Collector
+}
+
+func (c *captureRegisterer) Register(collector prometheus.Collector) error {
+	c.collector = collector
+	return nil
+}
+
+type descCollector struct {
+	desc *prometheus.Desc
+}
+
+func (d descCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- d.desc
+}
+
+func (d descCollector) Collect(chan<- prometheus.Metric) {}
+
+type metricCollector struct {
+	metric prometheus.Metric
+}
+
+func (m metricCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.metric.Desc()
+}
+
+func (m metricCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- m.metric
+}
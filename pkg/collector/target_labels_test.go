@@ -0,0 +1,76 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type staticCollector struct {
+	desc   *prometheus.Desc
+	labels [][]string
+}
+
+func (s staticCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.desc
+}
+
+func (s staticCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, labels := range s.labels {
+		ch <- prometheus.MustNewConstMetric(s.desc, prometheus.GaugeValue, 1, labels...)
+	}
+}
+
+// TestWithTargetLabels tests that target labels and const labels are added to
+// the series of matching targets only, never override existing labels and
+// are consistent with the described descriptors.
+func TestWithTargetLabels(t *testing.T) {
+	desc := prometheus.NewDesc("test_balance", "Returns a test balance", []string{"account", "status"}, nil)
+	c := staticCollector{
+		desc:   desc,
+		labels: [][]string{{"warden1faucet", successStatus}, {"warden1other", successStatus}},
+	}
+
+	targetLabels := map[string]map[string]string{
+		"warden1faucet": {"alias": "faucet", "status": "overridden"},
+		"prod":          {"owner": "ml"},
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registerer := prometheus.WrapRegistererWith(prometheus.Labels{"environment": "test"}, registry)
+	registerer.MustRegister(WithTargetLabels(c, targetLabels))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %s", err)
+	}
+
+	if len(families) != 1 || len(families[0].GetMetric()) != 2 {
+		t.Fatalf("Gather() = %v, want one family with two series", families)
+	}
+
+	for _, metric := range families[0].GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+
+		if labels["environment"] != "test" {
+			t.Errorf("series %v is missing the const label", labels)
+		}
+		if labels["status"] != successStatus {
+			t.Errorf("series %v has an overridden status label", labels)
+		}
+
+		wantAlias := ""
+		if labels["account"] == "warden1faucet" {
+			wantAlias = "faucet"
+		}
+		if labels["alias"] != wantAlias {
+			t.Errorf("series %v has alias %q, want %q", labels, labels["alias"], wantAlias)
+		}
+		if labels["owner"] != "" {
+			t.Errorf("series %v has the owner label of another target", labels)
+		}
+	}
+}
@@ -33,6 +33,7 @@ var (
 		tavilyPlanUsageMetricName,
 		"Returns Tavily API account plan usage (credits consumed in current billing cycle)",
 		[]string{
+			"account",
 			"plan",
 			"unit",
 			"status",
@@ -44,6 +45,7 @@ var (
 		tavilyPlanLimitMetricName,
 		"Returns Tavily API account plan limit (credit ceiling for current billing cycle)",
 		[]string{
+			"account",
 			"plan",
 			"unit",
 			"status",
@@ -68,9 +70,19 @@ func (c TavilyCollector) Collect(ch chan<- prometheus.Metric) {
 	)
 	defer cancel()
 
+	for _, account := range config.ParseAPIAccounts(c.Cfg.TavilyAPIKey) {
+		c.collectAccount(ctx, ch, account)
+	}
+}
+
+func (c TavilyCollector) collectAccount(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account config.APIAccount,
+) {
 	status := successStatus
 
-	response, err := c.tavilyCollectUsage(ctx)
+	response, err := c.tavilyCollectUsage(ctx, account.Key)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting Tavily usage %s", err))
 		status = errorStatus
@@ -82,6 +94,7 @@ func (c TavilyCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		response.Account.PlanUsage,
 		[]string{
+			account.Name,
 			response.Account.CurrentPlan,
 			"credits",
 			status,
@@ -93,6 +106,7 @@ func (c TavilyCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		response.Account.PlanLimit,
 		[]string{
+			account.Name,
 			response.Account.CurrentPlan,
 			"credits",
 			status,
@@ -100,10 +114,10 @@ func (c TavilyCollector) Collect(ch chan<- prometheus.Metric) {
 	)
 }
 
-func (c TavilyCollector) tavilyCollectUsage(ctx context.Context) (TavilyUsageResponse, error) {
+func (c TavilyCollector) tavilyCollectUsage(ctx context.Context, apiKey string) (TavilyUsageResponse, error) {
 	url := fmt.Sprintf("%s/usage", tavilyAPIURL)
 
-	data, err := http.GetRequest(ctx, url, c.Cfg.TavilyAPIKey, c.Cfg.HTTPTimeout)
+	data, err := http.GetRequest(ctx, url, apiKey, c.Cfg.HTTPTimeout)
 	if err != nil {
 		return TavilyUsageResponse{}, err
 	}
@@ -0,0 +1,263 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	blockGasWantedMetricName = "cosmos_chain_block_gas_wanted"
+	blockGasUsedMetricName   = "cosmos_chain_block_gas_used"
+	windowTxsMetricName      = "cosmos_chain_window_txs"
+	failedTxRatioMetricName  = "cosmos_chain_failed_tx_ratio"
+	windowMsgsMetricName     = "cosmos_chain_window_msgs"
+	txSuccessResult          = "success"
+	txFailedResult           = "failed"
+	// blockResultsConcurrency bounds the block_results calls in flight.
+	blockResultsConcurrency = 8
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	blockGasWanted = prometheus.NewDesc(
+		blockGasWantedMetricName,
+		"Returns the gas wanted by the transactions of a block over the recent window by statistic (avg, p95, max).",
+		[]string{
+			"chain_id",
+			"stat",
+			"status",
+		},
+		nil,
+	)
+
+	blockGasUsed = prometheus.NewDesc(
+		blockGasUsedMetricName,
+		"Returns the gas used by the transactions of a block over the recent window by statistic (avg, p95, max).",
+		[]string{
+			"chain_id",
+			"stat",
+			"status",
+		},
+		nil,
+	)
+
+	windowTxs = prometheus.NewDesc(
+		windowTxsMetricName,
+		"Returns the number of transactions in the recent window by result (success, failed).",
+		[]string{
+			"chain_id",
+			"result",
+			"status",
+		},
+		nil,
+	)
+
+	failedTxRatio = prometheus.NewDesc(
+		failedTxRatioMetricName,
+		"Returns the share of failed transactions in the recent window.",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	windowMsgs = prometheus.NewDesc(
+		windowMsgsMetricName,
+		"Returns the number of messages in the recent window by message type URL and transaction result.",
+		[]string{
+			"chain_id",
+			"msg_type",
+			"result",
+			"status",
+		},
+		nil,
+	)
+)
+
+// CometBFTBlockResults is the part of the CometBFT block_results response the
+// exporter uses.
+type CometBFTBlockResults struct {
+	TxsResults []CometBFTTxResult `json:"txs_results"`
+}
+
+type CometBFTTxResult struct {
+	Code      uint32 `json:"code"`
+	GasWanted string `json:"gas_wanted"`
+	GasUsed   string `json:"gas_used"`
+}
+
+type msgGroup struct {
+	msgType string
+	result  string
+}
+
+// txStats holds the transaction statistics of a window of blocks.
+type txStats struct {
+	gasWanted []float64
+	gasUsed   []float64
+	txs       map[string]int
+	msgs      map[msgGroup]int
+}
+
+func describeTxStats(ch chan<- *prometheus.Desc) {
+	ch <- blockGasWanted
+	ch <- blockGasUsed
+	ch <- windowTxs
+	ch <- failedTxRatio
+	ch <- windowMsgs
+}
+
+// collectTxStats exports gas, transaction results and message types of the
+// last blockCount blocks. Transaction results are read from the CometBFT RPC,
+// blocks whose results cannot be fetched are skipped and the series are
+// exported with the error status.
+func collectTxStats(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	cfg config.Config,
+	blocks []grpc.BlockStats,
+	blockCount int64,
+) {
+	status := successStatus
+	stats := txStats{
+		txs:  map[string]int{txSuccessResult: 0, txFailedResult: 0},
+		msgs: map[msgGroup]int{},
+	}
+
+	if len(blocks) == 0 {
+		status = errorStatus
+	} else {
+		startHeight := blocks[len(blocks)-1].Height - blockCount + 1
+		window := blocks
+		for len(window) > 0 && window[0].Height < startHeight {
+			window = window[1:]
+		}
+
+		results, failedBlocks := fetchBlockResults(ctx, cfg, window)
+		if failedBlocks > 0 {
+			log.Error(fmt.Sprintf("error fetching the results of %d blocks", failedBlocks))
+			status = errorStatus
+		}
+
+		for i, block := range window {
+			if results[i] != nil {
+				stats.add(block, *results[i])
+			}
+		}
+	}
+
+	if len(stats.gasWanted) > 0 {
+		collectWindowStats(ch, blockGasWanted, cfg.ChainID, stats.gasWanted, status)
+		collectWindowStats(ch, blockGasUsed, cfg.ChainID, stats.gasUsed, status)
+	}
+
+	total := 0
+	for result, count := range stats.txs {
+		total += count
+
+		ch <- prometheus.MustNewConstMetric(
+			windowTxs,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{cfg.ChainID, result, status}...,
+		)
+	}
+
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(stats.txs[txFailedResult]) / float64(total)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		failedTxRatio,
+		prometheus.GaugeValue,
+		ratio,
+		[]string{cfg.ChainID, status}...,
+	)
+
+	for group, count := range stats.msgs {
+		ch <- prometheus.MustNewConstMetric(
+			windowMsgs,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{cfg.ChainID, group.msgType, group.result, status}...,
+		)
+	}
+}
+
+// fetchBlockResults fetches the results of blocks concurrently, at most
+// blockResultsConcurrency at a time. Results are in block order and nil for
+// the blocks that could not be fetched.
+func fetchBlockResults(
+	ctx context.Context,
+	cfg config.Config,
+	blocks []grpc.BlockStats,
+) ([]*CometBFTBlockResults, int) {
+	results := make([]*CometBFTBlockResults, len(blocks))
+	sem := make(chan struct{}, blockResultsConcurrency)
+
+	var wg sync.WaitGroup
+	for i, block := range blocks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var res CometBFTBlockResults
+			path := fmt.Sprintf("/block_results?height=%d", block.Height)
+			if err := cometBFTGet(ctx, cfg, path, &res); err != nil {
+				log.Debug(fmt.Sprintf("Error fetching block results %d: %s", block.Height, err))
+				return
+			}
+			results[i] = &res
+		}()
+	}
+	wg.Wait()
+
+	failedBlocks := 0
+	for _, res := range results {
+		if res == nil {
+			failedBlocks++
+		}
+	}
+
+	return results, failedBlocks
+}
+
+// add records the transactions of a block with their results. Results are in
+// block order, transactions that are not Cosmos transactions are left out.
+func (s *txStats) add(block grpc.BlockStats, results CometBFTBlockResults) {
+	var gasWanted, gasUsed float64
+
+	for i, result := range results.TxsResults {
+		if i >= len(block.MsgTypes) || block.MsgTypes[i] == nil {
+			continue
+		}
+
+		gasWanted += parseCometBFTNumber(result.GasWanted)
+		gasUsed += parseCometBFTNumber(result.GasUsed)
+
+		txResult := txSuccessResult
+		if result.Code != 0 {
+			txResult = txFailedResult
+		}
+		s.txs[txResult]++
+
+		for _, msgType := range block.MsgTypes[i] {
+			s.msgs[msgGroup{msgType: msgType, result: txResult}]++
+		}
+	}
+
+	s.gasWanted = append(s.gasWanted, gasWanted)
+	s.gasUsed = append(s.gasUsed, gasUsed)
+}
@@ -0,0 +1,149 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+)
+
+// TestTxStatsAdd tests that transactions are counted by result with their
+// gas and message types, leaving out transactions that are not Cosmos
+// transactions.
+func TestTxStatsAdd(t *testing.T) {
+	stats := txStats{
+		txs:  map[string]int{},
+		msgs: map[msgGroup]int{},
+	}
+
+	block := grpc.BlockStats{
+		Height: 10,
+		MsgTypes: [][]string{
+			nil,
+			{"/cosmos.bank.v1beta1.MsgSend"},
+			{"/cosmos.bank.v1beta1.MsgSend", "/warden.act.v1beta1.MsgNewAction"},
+		},
+	}
+
+	results := CometBFTBlockResults{TxsResults: []CometBFTTxResult{
+		// Oracle data injected by the proposer fails to decode as a transaction
+		{Code: 2, GasWanted: "0", GasUsed: "0"},
+		{Code: 0, GasWanted: "100", GasUsed: "80"},
+		{Code: 5, GasWanted: "200", GasUsed: "150"},
+	}}
+
+	stats.add(block, results)
+
+	if stats.txs[txSuccessResult] != 1 || stats.txs[txFailedResult] != 1 {
+		t.Errorf("txs = %v, want 1 success and 1 failed", stats.txs)
+	}
+
+	if len(stats.gasWanted) != 1 || stats.gasWanted[0] != 300 || stats.gasUsed[0] != 230 {
+		t.Errorf("gas wanted = %v, gas used = %v, want [300] and [230]", stats.gasWanted, stats.gasUsed)
+	}
+
+	want := map[msgGroup]int{
+		{msgType: "/cosmos.bank.v1beta1.MsgSend", result: txSuccessResult}:    1,
+		{msgType: "/cosmos.bank.v1beta1.MsgSend", result: txFailedResult}:     1,
+		{msgType: "/warden.act.v1beta1.MsgNewAction", result: txFailedResult}: 1,
+	}
+	if len(stats.msgs) != len(want) {
+		t.Errorf("msgs = %v, want %v", stats.msgs, want)
+	}
+	for group, count := range want {
+		if stats.msgs[group] != count {
+			t.Errorf("msgs[%v] = %d, want %d", group, stats.msgs[group], count)
+		}
+	}
+}
+
+// txStatsCollector collects the transaction statistics of a fixed window of
+// blocks.
+type txStatsCollector struct {
+	cfg        config.Config
+	blocks     []grpc.BlockStats
+	blockCount int64
+}
+
+func (c txStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	describeTxStats(ch)
+}
+
+func (c txStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	collectTxStats(context.Background(), ch, c.cfg, c.blocks, c.blockCount)
+}
+
+// TestCollectTxStats tests that only the last blockCount blocks are fetched
+// and that a block whose results cannot be fetched sets the error status.
+func TestCollectTxStats(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height := r.URL.Query().Get("height")
+
+		mu.Lock()
+		requested = append(requested, height)
+		mu.Unlock()
+
+		if height == "13" {
+			http.Error(w, "block results pruned", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result": {"txs_results": [{"code": 0, "gas_wanted": "100", "gas_used": "80"}]}}`))
+	}))
+	defer server.Close()
+
+	blocks := []grpc.BlockStats{}
+	for height := int64(10); height <= 14; height++ {
+		blocks = append(blocks, grpc.BlockStats{
+			Height:   height,
+			TxCount:  1,
+			MsgTypes: [][]string{{"/cosmos.bank.v1beta1.MsgSend"}},
+		})
+	}
+
+	c := txStatsCollector{
+		cfg:        config.Config{ChainID: "warden_8765-1", CometBFTRPCURL: server.URL, HTTPTimeout: 5},
+		blocks:     blocks,
+		blockCount: 3,
+	}
+
+	metrics := collectGauges(t, c)
+
+	if len(requested) != 3 {
+		t.Errorf("requested the results of heights %v, want 12 to 14", requested)
+	}
+
+	for _, m := range metrics[windowTxs] {
+		labels := metricLabels(m)
+		if labels["status"] != errorStatus {
+			t.Errorf("windowTxs labels = %v, want the error status", labels)
+		}
+		if labels["result"] == txSuccessResult && m.GetGauge().GetValue() != 2 {
+			t.Errorf("successful txs = %v, want 2", m.GetGauge().GetValue())
+		}
+	}
+
+	if len(metrics[blockGasWanted]) != 3 || metricLabels(metrics[blockGasWanted][0])["status"] != errorStatus {
+		t.Errorf("unexpected gas wanted series %v", metrics[blockGasWanted])
+	}
+}
+
+// TestCollectTxStatsNoBlocks tests that an empty window is exported with the
+// error status.
+func TestCollectTxStatsNoBlocks(t *testing.T) {
+	metrics := collectGauges(t, txStatsCollector{cfg: config.Config{ChainID: "warden_8765-1"}, blockCount: 3})
+
+	if len(metrics[failedTxRatio]) != 1 || metricLabels(metrics[failedTxRatio][0])["status"] != errorStatus {
+		t.Errorf("unexpected failed tx ratio series %v", metrics[failedTxRatio])
+	}
+}
@@ -0,0 +1,177 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const statusLabel = "status"
+
+// upSeriesCollector removes the status label from the series of a collector,
+// omits failed series and reports the outcome per target as a <name>_up
+// series instead.
+type upSeriesCollector struct {
+	prometheus.Collector
+
+	name   string
+	upDesc *prometheus.Desc
+}
+
+// failureReporter is implemented by collectors that can fail without
+// exporting a series with the error status, e.g. when the series are only
+// known from the response that failed.
+type failureReporter interface {
+	// collectFailures collects like Collect and returns the targets whose
+	// collection failed, an empty target standing for the whole collector.
+	collectFailures(ch chan<- prometheus.Metric) []string
+}
+
+// WithUpSeries wraps c for UP_METRICS mode. Series with status "error" are
+// dropped, the status label is removed from the rest and <name>_up is 1 for
+// every target whose series all succeeded and 0 otherwise. The target is the
+// value of the first identifying label of a series (see targetLabelNames),
+// or name for collectors without one. Series without a status label are
+// passed on as is. Targets reported by a failureReporter are down, and so is
+// name when c exports no series with a status label at all.
+func WithUpSeries(c prometheus.Collector, name string) prometheus.Collector {
+	return upSeriesCollector{
+		Collector: c,
+		name:      name,
+		upDesc: prometheus.NewDesc(
+			name+"_up",
+			fmt.Sprintf("Returns 1 if the last %s collection of target succeeded, 0 otherwise", name),
+			[]string{
+				"target",
+			},
+			nil,
+		),
+	}
+}
+
+func (u upSeriesCollector) Describe(ch chan<- *prometheus.Desc) {
+	u.Collector.Describe(ch)
+	ch <- u.upDesc
+}
+
+func (u upSeriesCollector) Collect(ch chan<- prometheus.Metric) {
+	var failures []string
+
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		if reporter, ok := u.Collector.(failureReporter); ok {
+			failures = reporter.collectFailures(metrics)
+		} else {
+			u.Collector.Collect(metrics)
+		}
+		close(metrics)
+	}()
+
+	up := map[string]bool{}
+	for m := range metrics {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			// Pass the metric on as is, the registry reports the error.
+			ch <- m
+			continue
+		}
+
+		status, target, ok := u.statusAndTarget(&metric)
+		if !ok {
+			ch <- m
+			continue
+		}
+
+		succeeded := status != errorStatus
+		if prev, seen := up[target]; seen {
+			succeeded = succeeded && prev
+		}
+		up[target] = succeeded
+
+		if status != errorStatus {
+			ch <- withoutLabelMetric{Metric: m, label: statusLabel}
+		}
+	}
+
+	for _, target := range failures {
+		if target == "" {
+			target = u.name
+		}
+		up[target] = false
+	}
+
+	// A collector without any series with a status label failed before it
+	// could export one
+	if len(up) == 0 {
+		up[u.name] = false
+	}
+
+	targets := make([]string, 0, len(up))
+	for target := range up {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		value := 0.0
+		if up[target] {
+			value = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			u.upDesc,
+			prometheus.GaugeValue,
+			value,
+			[]string{target}...,
+		)
+	}
+}
+
+// statusAndTarget returns the status and target of a series. The last return
+// value is false for series without a status label.
+func (u upSeriesCollector) statusAndTarget(metric *dto.Metric) (string, string, bool) {
+	var status, target string
+	hasStatus := false
+
+	for _, label := range metric.GetLabel() {
+		switch {
+		case label.GetName() == statusLabel:
+			status = label.GetValue()
+			hasStatus = true
+		case target == "" && targetLabelNames[label.GetName()]:
+			target = label.GetValue()
+		}
+	}
+
+	if target == "" {
+		target = u.name
+	}
+
+	return status, target, hasStatus
+}
+
+// withoutLabelMetric is a metric with one of the labels of its descriptor
+// removed.
+type withoutLabelMetric struct {
+	prometheus.Metric
+
+	label string
+}
+
+func (w withoutLabelMetric) Write(out *dto.Metric) error {
+	if err := w.Metric.Write(out); err != nil {
+		return err
+	}
+
+	labels := out.Label[:0]
+	for _, label := range out.GetLabel() {
+		if label.GetName() != w.label {
+			labels = append(labels, label)
+		}
+	}
+	out.Label = labels
+
+	return nil
+}
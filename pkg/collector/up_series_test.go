@@ -0,0 +1,137 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestWithUpSeries tests that failed series are dropped, the status label is
+// removed and the up series reflects the outcome per target.
+func TestWithUpSeries(t *testing.T) {
+	desc := prometheus.NewDesc("test_balance", "Returns a test balance", []string{"account", "symbol", "status"}, nil)
+	c := staticCollector{
+		desc: desc,
+		labels: [][]string{
+			{"faucet", "ETH", successStatus},
+			{"treasury", "ETH", successStatus},
+			{"treasury", "BNB", errorStatus},
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(WithUpSeries(c, "test"))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %s", err)
+	}
+
+	got := map[string][]map[string]string{}
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			got[family.GetName()] = append(got[family.GetName()], labels)
+			if family.GetName() == "test_up" {
+				values[labels["target"]] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	balances := got["test_balance"]
+	if len(balances) != 2 {
+		t.Fatalf("test_balance has %d series, want 2", len(balances))
+	}
+	for _, labels := range balances {
+		if _, ok := labels[statusLabel]; ok {
+			t.Errorf("series %v still has a status label", labels)
+		}
+	}
+
+	if values["faucet"] != 1 || values["treasury"] != 0 || len(values) != 2 {
+		t.Errorf("test_up = %v, want faucet=1 treasury=0", values)
+	}
+}
+
+// failingCollector reports failed targets without exporting any series.
+type failingCollector struct {
+	staticCollector
+
+	failed []string
+}
+
+func (f failingCollector) Collect(ch chan<- prometheus.Metric) {
+	f.collectFailures(ch)
+}
+
+func (f failingCollector) collectFailures(ch chan<- prometheus.Metric) []string {
+	f.staticCollector.Collect(ch)
+	return f.failed
+}
+
+// gatherUp returns the up series of the collector wrapped as name by target.
+func gatherUp(t *testing.T, c prometheus.Collector, name string) map[string]float64 {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(WithUpSeries(c, name))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %s", err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != name+"_up" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "target" {
+					values[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// TestWithUpSeriesNoSeries tests that a collector exporting nothing is down.
+func TestWithUpSeriesNoSeries(t *testing.T) {
+	desc := prometheus.NewDesc("test_balance", "Returns a test balance", []string{"account", "status"}, nil)
+
+	values := gatherUp(t, staticCollector{desc: desc}, "test")
+	if values["test"] != 0 || len(values) != 1 {
+		t.Errorf("test_up = %v, want test=0", values)
+	}
+}
+
+// TestWithUpSeriesFailures tests that reported failures mark their targets
+// down, the empty target standing for the collector.
+func TestWithUpSeriesFailures(t *testing.T) {
+	desc := prometheus.NewDesc("test_balance", "Returns a test balance", []string{"account", "status"}, nil)
+	c := failingCollector{
+		staticCollector: staticCollector{
+			desc:   desc,
+			labels: [][]string{{"faucet", successStatus}, {"treasury", successStatus}},
+		},
+		failed: []string{"treasury", ""},
+	}
+
+	values := gatherUp(t, c, "test")
+	want := map[string]float64{"faucet": 1, "treasury": 0, "test": 0}
+	if len(values) != len(want) {
+		t.Fatalf("test_up = %v, want %v", values, want)
+	}
+	for target, value := range want {
+		if values[target] != value {
+			t.Errorf("test_up{target=%q} = %v, want %v", target, values[target], value)
+		}
+	}
+}
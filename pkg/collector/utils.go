@@ -8,6 +8,7 @@ import (
 	"io"
 	"math/big"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 )
@@ -15,6 +16,8 @@ import (
 const (
 	errorStatus   = "error"
 	successStatus = "success"
+	otherGroup    = "other"
+	unknownGroup  = "unknown"
 )
 
 func splitCommaList(s string) []string {
@@ -29,19 +32,51 @@ func splitCommaList(s string) []string {
 	return out
 }
 
-func redactKey(k string) string {
-	if len(k) < 8 {
-		return "***"
+// usageGroup is a set of usage values reported for one combination of
+// group labels, e.g. a model and an API key.
+type usageGroup struct {
+	labels []string
+	values []float64
+}
+
+// groupValue returns the value of a group_by field, which usage and cost
+// APIs report as null for usage that cannot be attributed.
+func groupValue(v *string) string {
+	if v == nil || *v == "" {
+		return unknownGroup
+	}
+
+	return *v
+}
+
+// capUsageGroups keeps the maxGroups groups with the highest first value and
+// folds the remaining ones into a single group labelled "other", so a
+// provider returning many models or keys cannot blow up series cardinality.
+// A maxGroups of zero or less disables the cap.
+func capUsageGroups(groups []usageGroup, maxGroups int) []usageGroup {
+	if maxGroups <= 0 || len(groups) <= maxGroups {
+		return groups
 	}
-	prefix := k
-	if len(prefix) > 12 {
-		prefix = k[:12]
+
+	sorted := append([]usageGroup{}, groups...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].values[0] > sorted[j].values[0]
+	})
+
+	other := usageGroup{
+		labels: make([]string, len(sorted[0].labels)),
+		values: make([]float64, len(sorted[0].values)),
+	}
+	for i := range other.labels {
+		other.labels[i] = otherGroup
 	}
-	suffix := k
-	if len(suffix) > 3 {
-		suffix = k[len(k)-3:]
+	for _, g := range sorted[maxGroups:] {
+		for i, v := range g.values {
+			other.values[i] += v
+		}
 	}
-	return prefix + "..." + suffix
+
+	return append(sorted[:maxGroups:maxGroups], other)
 }
 
 type JSONRPCRequest struct {
@@ -52,10 +87,10 @@ type JSONRPCRequest struct {
 }
 
 type JSONRPCResponse struct {
-	JSONRPC string    `json:"jsonrpc"`
-	ID      int       `json:"id"`
-	Result  string    `json:"result,omitempty"`
-	Error   *RPCError `json:"error,omitempty"`
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
 }
 
 type RPCError struct {
@@ -63,17 +98,19 @@ type RPCError struct {
 	Message string `json:"message"`
 }
 
-func getBalance(ctx context.Context, rpc, address string, timeout int) (float64, error) {
+// callJSONRPC calls an Ethereum JSON-RPC method and decodes its result into
+// result.
+func callJSONRPC(ctx context.Context, rpc, method string, params []any, timeout int, result any) error {
 	reqBody := JSONRPCRequest{
 		JSONRPC: "2.0",
-		Method:  "eth_getBalance",
-		Params:  []any{address, "latest"},
+		Method:  method,
+		Params:  params,
 		ID:      1,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return 0, fmt.Errorf("error marshaling request: %w", err)
+		return fmt.Errorf("error marshaling request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(
@@ -83,7 +120,7 @@ func getBalance(ctx context.Context, rpc, address string, timeout int) (float64,
 		bytes.NewBuffer(jsonData),
 	)
 	if err != nil {
-		return 0, fmt.Errorf("error creating request: %w", err)
+		return fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -94,28 +131,51 @@ func getBalance(ctx context.Context, rpc, address string, timeout int) (float64,
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("error performing request: %w", err)
+		return fmt.Errorf("error performing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("error reading response body: %w", err)
+		return fmt.Errorf("error reading response body: %w", err)
 	}
 
 	var rpcResp JSONRPCResponse
 	if err = json.Unmarshal(body, &rpcResp); err != nil {
-		return 0, fmt.Errorf("error unmarshaling response: %w", err)
+		return fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
 	if rpcResp.Error != nil {
-		return 0, fmt.Errorf("RPC error: %s", rpcResp.Error.Message)
+		return fmt.Errorf("RPC error: %s", rpcResp.Error.Message)
+	}
+
+	if err = json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("error unmarshaling %s result: %w", method, err)
+	}
+
+	return nil
+}
+
+// parseHexQuantity parses a hex encoded JSON-RPC quantity such as 0x1bc16.
+func parseHexQuantity(quantity string) (*big.Int, error) {
+	value := new(big.Int)
+	if _, success := value.SetString(strings.TrimPrefix(quantity, "0x"), 16); !success {
+		return nil, fmt.Errorf("error parsing quantity: %s", quantity)
+	}
+
+	return value, nil
+}
+
+func getBalance(ctx context.Context, rpc, address string, timeout int) (float64, error) {
+	var result string
+	if err := callJSONRPC(ctx, rpc, "eth_getBalance", []any{address, "latest"}, timeout, &result); err != nil {
+		return 0, err
 	}
 
 	// Convert hex balance to decimal
-	balanceWei := new(big.Int)
-	if _, success := balanceWei.SetString(strings.TrimPrefix(rpcResp.Result, "0x"), 16); !success {
-		return 0, fmt.Errorf("error parsing balance: %s", rpcResp.Result)
+	balanceWei, err := parseHexQuantity(result)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing balance: %s", result)
 	}
 
 	// Convert Wei to None
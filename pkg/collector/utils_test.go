@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"testing"
+)
+
+// TestCapUsageGroups tests that groups beyond the cap are folded into "other".
+func TestCapUsageGroups(t *testing.T) {
+	groups := []usageGroup{
+		{labels: []string{"grok-a", "key-1"}, values: []float64{1, 10}},
+		{labels: []string{"grok-b", "key-1"}, values: []float64{5, 20}},
+		{labels: []string{"grok-c", "key-2"}, values: []float64{3, 30}},
+	}
+
+	capped := capUsageGroups(groups, 1)
+	if len(capped) != 2 {
+		t.Fatalf("capUsageGroups() returned %d groups, want 2", len(capped))
+	}
+
+	if capped[0].labels[0] != "grok-b" {
+		t.Errorf("top group = %s, want grok-b", capped[0].labels[0])
+	}
+
+	other := capped[1]
+	if other.labels[0] != otherGroup || other.labels[1] != otherGroup {
+		t.Errorf("other group labels = %v, want all %q", other.labels, otherGroup)
+	}
+	if other.values[0] != 4 || other.values[1] != 40 {
+		t.Errorf("other group values = %v, want [4 40]", other.values)
+	}
+
+	if got := capUsageGroups(groups, 0); len(got) != len(groups) {
+		t.Errorf("capUsageGroups() with no cap returned %d groups, want %d", len(got), len(groups))
+	}
+}
@@ -20,6 +20,10 @@ const (
 	avgBlockTimeMetricName   = "cosmos_chain_avg_block_time_seconds"
 	tokensMetricName         = "cosmos_validator_tokens"
 	delegatorSharesMetric    = "cosmos_validator_delegator_shares"
+
+	// avgBlockTimeSampleSize is the number of blocks the average block time is
+	// measured over.
+	avgBlockTimeSampleSize = 100
 )
 
 //nolint:gochecknoglobals // this is needed as it's used in multiple places
@@ -106,6 +110,10 @@ func (vc ValidatorsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- avgBlockTime
 	ch <- tokens
 	ch <- delegatorShares
+	describeBlockStats(ch)
+	if vc.Cfg.TxMetrics {
+		describeTxStats(ch)
+	}
 }
 
 func (vc ValidatorsCollector) Collect(ch chan<- prometheus.Metric) {
@@ -118,16 +126,18 @@ func (vc ValidatorsCollector) Collect(ch chan<- prometheus.Metric) {
 
 	defer cancel()
 
+	// Fetch the recent blocks once for proposer counts and block metrics
+	blocks, err := grpc.RecentBlocks(ctx, vc.Cfg, max(vc.Cfg.BlockWindow, avgBlockTimeSampleSize))
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting recent blocks: %s", err))
+	}
+
 	vals, err := grpc.SigningValidators(ctx, vc.Cfg)
 	if err != nil {
 		log.Error(fmt.Sprintf("error getting signing validators: %s", err))
 	} else {
 		// Get block proposer counts
-		proposerCounts, err = grpc.BlockProposers(ctx, vc.Cfg, vc.Cfg.BlockWindow)
-		if err != nil {
-			log.Error(fmt.Sprintf("error getting block proposers: %s", err))
-			proposerCounts = make(map[string]int64)
-		}
+		proposerCounts = grpc.BlockProposers(blocks, vc.Cfg.BlockWindow)
 
 		// Merge proposer counts into validator data
 		for i := range vals {
@@ -154,7 +164,7 @@ func (vc ValidatorsCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	// Get average block time
-	blockTime, err := grpc.AverageBlockTime(ctx, vc.Cfg, 100)
+	blockTime, err := grpc.AverageBlockTime(blocks, avgBlockTimeSampleSize)
 	if err != nil {
 		log.Error(fmt.Sprintf("error getting average block time: %s", err))
 	} else {
@@ -165,6 +175,12 @@ func (vc ValidatorsCollector) Collect(ch chan<- prometheus.Metric) {
 			vc.Cfg.ChainID,
 		)
 	}
+
+	collectBlockStats(ch, vc.Cfg.ChainID, blocks)
+
+	if vc.Cfg.TxMetrics {
+		collectTxStats(ctx, ch, vc.Cfg, blocks, vc.Cfg.BlockWindow)
+	}
 }
 
 func (vc ValidatorsCollector) missedBlocksMetrics(vals []validator.Validator) []prometheus.Metric {
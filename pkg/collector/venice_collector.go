@@ -16,9 +16,11 @@ import (
 )
 
 const (
-	veniceBillingMetricName = "venice_funds"
-	veniceUsageMetricName   = "venice_api_key_usage"
-	veniceAPIURL            = "https://api.venice.ai/api/v1"
+	veniceBillingMetricName   = "venice_funds"
+	veniceUsageMetricName     = "venice_api_key_usage"
+	veniceBurnRateMetricName  = "venice_funds_burn_rate_per_hour"
+	veniceDepletionMetricName = "venice_funds_depletion_seconds"
+	veniceAPIURL              = "https://api.venice.ai/api/v1"
 )
 
 type VeniceUsageResponse struct {
@@ -72,13 +74,43 @@ var veniceUsage = prometheus.NewDesc(
 	nil,
 )
 
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var veniceBurnRate = prometheus.NewDesc(
+	veniceBurnRateMetricName,
+	"Returns Venice funds spent per hour over the given window",
+	[]string{
+		"account",
+		"symbol",
+		"window",
+	},
+	nil,
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var veniceDepletion = prometheus.NewDesc(
+	veniceDepletionMetricName,
+	"Returns estimated seconds until Venice funds are depleted at the burn rate of the given window",
+	[]string{
+		"account",
+		"symbol",
+		"window",
+	},
+	nil,
+)
+
 type VeniceCollector struct {
-	Cfg config.Config
+	Cfg     config.Config
+	History *BalanceHistory
 }
 
 func (v VeniceCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- veniceBilling
 	ch <- veniceUsage
+
+	if v.History != nil {
+		ch <- veniceBurnRate
+		ch <- veniceDepletion
+	}
 }
 
 func (v VeniceCollector) Collect(ch chan<- prometheus.Metric) {
@@ -88,20 +120,19 @@ func (v VeniceCollector) Collect(ch chan<- prometheus.Metric) {
 	)
 	defer cancel()
 
-	for _, apiKey := range splitCommaList(v.Cfg.VeniceAPIKey) {
-		v.collectAccount(ctx, ch, apiKey)
+	for _, account := range config.ParseAPIAccounts(v.Cfg.VeniceAPIKey) {
+		v.collectAccount(ctx, ch, account)
 	}
 }
 
 func (v VeniceCollector) collectAccount(
 	ctx context.Context,
 	ch chan<- prometheus.Metric,
-	apiKey string,
+	account config.APIAccount,
 ) {
-	account := redactKey(apiKey)
 	status := successStatus
 
-	diemBalance, usdBalance, err := v.veniceCollectBalance(ctx, apiKey)
+	diemBalance, usdBalance, err := v.veniceCollectBalance(ctx, account.Key)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting Venice balance: %s", err))
 		status = errorStatus
@@ -112,7 +143,7 @@ func (v VeniceCollector) collectAccount(
 		prometheus.GaugeValue,
 		diemBalance,
 		[]string{
-			account,
+			account.Name,
 			"DIEM",
 			status,
 		}...,
@@ -123,17 +154,21 @@ func (v VeniceCollector) collectAccount(
 		prometheus.GaugeValue,
 		usdBalance,
 		[]string{
-			account,
+			account.Name,
 			"USD",
 			status,
 		}...,
 	)
 
+	if status == successStatus {
+		v.History.collect(ch, veniceBurnRate, veniceDepletion, account.Key, usdBalance, account.Name, "USD")
+	}
+
 	if !v.Cfg.VeniceUsageMetrics {
 		return
 	}
 
-	usage, err := v.veniceCollectUsage(ctx, apiKey)
+	usage, err := v.veniceCollectUsage(ctx, account.Key)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting Venice usage: %s", err))
 		status = errorStatus
@@ -146,7 +181,7 @@ func (v VeniceCollector) collectAccount(
 			veniceUsage,
 			prometheus.GaugeValue,
 			diemCount,
-			account,
+			account.Name,
 			data.ID,
 			data.Description,
 			"DIEM",
@@ -156,7 +191,7 @@ func (v VeniceCollector) collectAccount(
 			veniceUsage,
 			prometheus.GaugeValue,
 			usdCount,
-			account,
+			account.Name,
 			data.ID,
 			data.Description,
 			"USD",
@@ -0,0 +1,235 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	walletSequenceMetricName           = "cosmos_wallet_account_sequence"
+	walletFeegrantsMetricName          = "cosmos_wallet_feegrants"
+	walletFeegrantSpendLimitMetricName = "cosmos_wallet_feegrant_spend_limit"
+	walletFeegrantExpirationMetricName = "cosmos_wallet_feegrant_expiration_timestamp_seconds"
+	walletAuthzGrantsMetricName        = "cosmos_wallet_authz_grants"
+	walletAuthzExpirationMetricName    = "cosmos_wallet_authz_grant_expiration_timestamp_seconds"
+	totalSpendLimit                    = "total"
+	periodSpendLimit                   = "period"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	walletSequence = prometheus.NewDesc(
+		walletSequenceMetricName,
+		"Returns the sequence number of account, the number of transactions it has signed",
+		[]string{
+			"chain_id",
+			"account",
+			"status",
+		},
+		nil,
+	)
+
+	walletFeegrants = prometheus.NewDesc(
+		walletFeegrantsMetricName,
+		"Returns the number of fee grants given to account",
+		[]string{
+			"chain_id",
+			"account",
+			"status",
+		},
+		nil,
+	)
+
+	walletFeegrantSpendLimit = prometheus.NewDesc(
+		walletFeegrantSpendLimitMetricName,
+		"Returns what is left of the spend limit of a fee grant given to account, in total or in the current "+
+			"period of a periodic allowance",
+		[]string{
+			"chain_id",
+			"account",
+			"granter",
+			"denom",
+			"limit",
+		},
+		nil,
+	)
+
+	walletFeegrantExpiration = prometheus.NewDesc(
+		walletFeegrantExpirationMetricName,
+		"Returns the expiration of a fee grant given to account as a unix timestamp",
+		[]string{
+			"chain_id",
+			"account",
+			"granter",
+		},
+		nil,
+	)
+
+	walletAuthzGrants = prometheus.NewDesc(
+		walletAuthzGrantsMetricName,
+		"Returns the number of authz grants given to account",
+		[]string{
+			"chain_id",
+			"account",
+			"status",
+		},
+		nil,
+	)
+
+	walletAuthzExpiration = prometheus.NewDesc(
+		walletAuthzExpirationMetricName,
+		"Returns the expiration of an authz grant given to account as a unix timestamp",
+		[]string{
+			"chain_id",
+			"account",
+			"granter",
+			"authorization",
+		},
+		nil,
+	)
+)
+
+func describeAccountMetrics(ch chan<- *prometheus.Desc) {
+	ch <- walletSequence
+	ch <- walletFeegrants
+	ch <- walletFeegrantSpendLimit
+	ch <- walletFeegrantExpiration
+	ch <- walletAuthzGrants
+	ch <- walletAuthzExpiration
+}
+
+// collectAccountMetrics exports the sequence, fee grants and authz grants of
+// an account.
+func (w WalletBalanceCollector) collectAccountMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	addr string,
+) {
+	status := successStatus
+	sequence, err := client.AccountSequence(ctx, addr)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting sequence of account %s: %s", addr, err))
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		walletSequence,
+		prometheus.GaugeValue,
+		float64(sequence),
+		[]string{w.Cfg.ChainID, addr, status}...,
+	)
+
+	w.collectFeegrantMetrics(ctx, ch, client, addr)
+	w.collectAuthzMetrics(ctx, ch, client, addr)
+}
+
+func (w WalletBalanceCollector) collectFeegrantMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	addr string,
+) {
+	status := successStatus
+	allowances, err := client.FeeAllowances(ctx, addr)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting fee grants of account %s: %s", addr, err))
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		walletFeegrants,
+		prometheus.GaugeValue,
+		float64(len(allowances)),
+		[]string{w.Cfg.ChainID, addr, status}...,
+	)
+
+	for _, allowance := range allowances {
+		w.collectSpendLimit(ch, addr, allowance.Granter, totalSpendLimit, allowance.SpendLimit)
+		w.collectSpendLimit(ch, addr, allowance.Granter, periodSpendLimit, allowance.PeriodCanSpend)
+
+		if allowance.Expiration != nil {
+			ch <- prometheus.MustNewConstMetric(
+				walletFeegrantExpiration,
+				prometheus.GaugeValue,
+				float64(allowance.Expiration.Unix()),
+				[]string{w.Cfg.ChainID, addr, allowance.Granter}...,
+			)
+		}
+	}
+}
+
+// collectSpendLimit exports a spend limit of a fee grant, nothing is exported
+// for allowances without a limit.
+func (w WalletBalanceCollector) collectSpendLimit(
+	ch chan<- prometheus.Metric,
+	addr string,
+	granter string,
+	limit string,
+	coins sdk.Coins,
+) {
+	for _, coin := range coins {
+		exponent := 0
+		if coin.Denom == w.Cfg.Denom {
+			exponent = w.Cfg.Exponent
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			walletFeegrantSpendLimit,
+			prometheus.GaugeValue,
+			denomAmount(coin.Amount, exponent),
+			[]string{w.Cfg.ChainID, addr, granter, coin.Denom, limit}...,
+		)
+	}
+}
+
+func (w WalletBalanceCollector) collectAuthzMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	addr string,
+) {
+	status := successStatus
+	grants, err := client.AuthzGrants(ctx, addr)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting authz grants of account %s: %s", addr, err))
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		walletAuthzGrants,
+		prometheus.GaugeValue,
+		float64(len(grants)),
+		[]string{w.Cfg.ChainID, addr, status}...,
+	)
+
+	// A granter has one grant per message type, the earliest expiration is
+	// kept should two grants still share a label set
+	expirations := map[[2]string]time.Time{}
+	for _, grant := range grants {
+		if grant.Expiration == nil {
+			continue
+		}
+
+		key := [2]string{grant.Granter, grant.Authorization}
+		if expiration, ok := expirations[key]; !ok || grant.Expiration.Before(expiration) {
+			expirations[key] = *grant.Expiration
+		}
+	}
+
+	for key, expiration := range expirations {
+		ch <- prometheus.MustNewConstMetric(
+			walletAuthzExpiration,
+			prometheus.GaugeValue,
+			float64(expiration.Unix()),
+			[]string{w.Cfg.ChainID, addr, key[0], key[1]}...,
+		)
+	}
+}
@@ -16,28 +16,54 @@ import (
 )
 
 const (
-	walletBalanceMetricName = "cosmos_wallet_balance"
+	walletBalanceMetricName    = "cosmos_wallet_balance"
+	walletBalanceUSDMetricName = "cosmos_wallet_balance_usd"
 )
 
 //nolint:gochecknoglobals // this is needed as it's used in multiple places
-var walletBalance = prometheus.NewDesc(
-	walletBalanceMetricName,
-	"Returns the wallet balance of account",
-	[]string{
-		"chain_id",
-		"account",
-		"denom",
-		"status",
-	},
-	nil,
+var (
+	walletBalance = prometheus.NewDesc(
+		walletBalanceMetricName,
+		"Returns the wallet balance of account",
+		[]string{
+			"chain_id",
+			"account",
+			"denom",
+			"status",
+		},
+		nil,
+	)
+
+	walletBalanceUSD = prometheus.NewDesc(
+		walletBalanceUSDMetricName,
+		"Returns the USD value of the wallet balance of account",
+		[]string{
+			"chain_id",
+			"account",
+			"denom",
+			"symbol",
+			"status",
+		},
+		nil,
+	)
 )
 
 type WalletBalanceCollector struct {
-	Cfg config.Config
+	Cfg    config.Config
+	Prices *PriceFeed
 }
 
 func (w WalletBalanceCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- walletBalance
+	if w.Prices != nil {
+		ch <- walletBalanceUSD
+	}
+	if w.Cfg.WalletAccountMetrics {
+		describeAccountMetrics(ch)
+	}
+	if w.Cfg.WalletVestingMetrics {
+		describeVestingMetrics(ch)
+	}
 }
 
 func (w WalletBalanceCollector) Collect(ch chan<- prometheus.Metric) {
@@ -71,24 +97,7 @@ func (w WalletBalanceCollector) Collect(ch chan<- prometheus.Metric) {
 			log.Error(err.Error())
 			status = errorStatus
 		}
-		balanceBigInt := new(big.Int)
-		balanceBigInt.SetString(balanceRaw.String(), 10)
-
-		// Adjust based on your denomination
-		denomString := fmt.Sprintf("1e%d", w.Cfg.Exponent) // w.Cfg.Exponent assumed to be 18
-		denomFactor, ok := new(big.Float).SetString(denomString)
-		if !ok {
-			log.Error("Error parsing denominator factor")
-		}
-
-		// Create a *big.Float from the balance raw value
-		balanceBig := new(big.Float).SetInt(balanceBigInt)
-
-		// Perform the division
-		result := new(big.Float).Quo(balanceBig, denomFactor)
-
-		// Convert the result to float64 if necessary (note: this might still lead to a float64 approximation)
-		balance, _ := result.Float64()
+		balance := denomAmount(balanceRaw, w.Cfg.Exponent)
 
 		ch <- prometheus.MustNewConstMetric(
 			walletBalance,
@@ -101,5 +110,53 @@ func (w WalletBalanceCollector) Collect(ch chan<- prometheus.Metric) {
 				status,
 			}...,
 		)
+
+		if err == nil {
+			w.Prices.collectUSD(
+				ctx,
+				ch,
+				walletBalanceUSD,
+				w.Cfg.Symbol,
+				balance,
+				w.Cfg.ChainID,
+				addr,
+				w.Cfg.Denom,
+				w.Cfg.Symbol,
+				successStatus,
+			)
+		}
+
+		if w.Cfg.WalletAccountMetrics {
+			w.collectAccountMetrics(ctx, ch, client, addr)
+		}
+
+		if w.Cfg.WalletVestingMetrics {
+			w.collectVestingMetrics(ctx, ch, client, addr, time.Now())
+		}
 	}
 }
+
+// denomAmount converts a raw amount in the base denom to whole tokens.
+func denomAmount(raw math.Int, exponent int) float64 {
+	balanceBigInt := new(big.Int)
+	balanceBigInt.SetString(raw.String(), 10)
+
+	// Adjust based on your denomination
+	denomString := fmt.Sprintf("1e%d", exponent)
+	denomFactor, ok := new(big.Float).SetString(denomString)
+	if !ok {
+		log.Error("Error parsing denominator factor")
+		return 0
+	}
+
+	// Create a *big.Float from the balance raw value
+	balanceBig := new(big.Float).SetInt(balanceBigInt)
+
+	// Perform the division
+	result := new(big.Float).Quo(balanceBig, denomFactor)
+
+	// Convert the result to float64 if necessary (note: this might still lead to a float64 approximation)
+	balance, _ := result.Float64()
+
+	return balance
+}
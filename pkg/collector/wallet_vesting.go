@@ -0,0 +1,161 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	walletSpendableMetricName        = "cosmos_wallet_spendable_balance"
+	walletVestingAmountMetricName    = "cosmos_wallet_vesting_amount"
+	walletVestingNextEventMetricName = "cosmos_wallet_vesting_next_event_timestamp_seconds"
+	walletVestingEndMetricName       = "cosmos_wallet_vesting_end_timestamp_seconds"
+	vestedState                      = "vested"
+	unvestedState                    = "unvested"
+	delegatedVestingState            = "delegated_vesting"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	walletSpendable = prometheus.NewDesc(
+		walletSpendableMetricName,
+		"Returns the wallet balance of account that is neither locked by vesting nor delegated",
+		[]string{
+			"chain_id",
+			"account",
+			"denom",
+			"status",
+		},
+		nil,
+	)
+
+	walletVestingAmount = prometheus.NewDesc(
+		walletVestingAmountMetricName,
+		"Returns the vested, unvested and delegated vesting amounts of a vesting account",
+		[]string{
+			"chain_id",
+			"account",
+			"denom",
+			"account_type",
+			"state",
+			"status",
+		},
+		nil,
+	)
+
+	walletVestingNextEvent = prometheus.NewDesc(
+		walletVestingNextEventMetricName,
+		"Returns when the next amount of a vesting account vests as a unix timestamp, the end of the "+
+			"schedule for continuous vesting",
+		[]string{
+			"chain_id",
+			"account",
+			"account_type",
+		},
+		nil,
+	)
+
+	walletVestingEnd = prometheus.NewDesc(
+		walletVestingEndMetricName,
+		"Returns the end of the vesting schedule of a vesting account as a unix timestamp",
+		[]string{
+			"chain_id",
+			"account",
+			"account_type",
+		},
+		nil,
+	)
+)
+
+func describeVestingMetrics(ch chan<- *prometheus.Desc) {
+	ch <- walletSpendable
+	ch <- walletVestingAmount
+	ch <- walletVestingNextEvent
+	ch <- walletVestingEnd
+}
+
+// collectVestingMetrics exports the spendable balance of an account and, for
+// vesting accounts, its vesting schedule.
+func (w WalletBalanceCollector) collectVestingMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	addr string,
+	now time.Time,
+) {
+	status := successStatus
+	spendable, err := client.SpendableBalance(ctx, addr, w.Cfg.Denom)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting spendable balance of account %s: %s", addr, err))
+		status = errorStatus
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		walletSpendable,
+		prometheus.GaugeValue,
+		denomAmount(spendable, w.Cfg.Exponent),
+		[]string{w.Cfg.ChainID, addr, w.Cfg.Denom, status}...,
+	)
+
+	account, vesting, err := client.VestingAccount(ctx, addr)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting vesting schedule of account %s: %s", addr, err))
+
+		for _, state := range []string{vestedState, unvestedState, delegatedVestingState} {
+			ch <- prometheus.MustNewConstMetric(
+				walletVestingAmount,
+				prometheus.GaugeValue,
+				0,
+				[]string{w.Cfg.ChainID, addr, w.Cfg.Denom, unknownGroup, state, errorStatus}...,
+			)
+		}
+
+		return
+	}
+
+	if !vesting {
+		return
+	}
+
+	vested := account.Vested(w.Cfg.Denom, now)
+	amounts := map[string]math.Int{
+		vestedState:           vested,
+		unvestedState:         account.OriginalVesting.AmountOf(w.Cfg.Denom).Sub(vested),
+		delegatedVestingState: account.DelegatedVesting.AmountOf(w.Cfg.Denom),
+	}
+
+	for state, amount := range amounts {
+		ch <- prometheus.MustNewConstMetric(
+			walletVestingAmount,
+			prometheus.GaugeValue,
+			denomAmount(amount, w.Cfg.Exponent),
+			[]string{w.Cfg.ChainID, addr, w.Cfg.Denom, account.Type, state, successStatus}...,
+		)
+	}
+
+	if next, ok := account.NextEvent(now); ok {
+		ch <- prometheus.MustNewConstMetric(
+			walletVestingNextEvent,
+			prometheus.GaugeValue,
+			float64(next.Unix()),
+			[]string{w.Cfg.ChainID, addr, account.Type}...,
+		)
+	}
+
+	// Permanently locked accounts never end vesting
+	if !account.EndTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			walletVestingEnd,
+			prometheus.GaugeValue,
+			float64(account.EndTime.Unix()),
+			[]string{w.Cfg.ChainID, addr, account.Type}...,
+		)
+	}
+}
@@ -0,0 +1,584 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/prometheus/client_golang/prometheus"
+	act "github.com/warden-protocol/wardenprotocol/warden/x/act/types/v1beta1"
+	warden "github.com/warden-protocol/wardenprotocol/warden/x/warden/types/v1beta3"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	wardenKeyRequestsMetricName          = "warden_keychain_key_requests"
+	wardenSignRequestsMetricName         = "warden_keychain_sign_requests"
+	wardenOldestPendingRequestMetricName = "warden_keychain_oldest_pending_request_age_seconds"
+	wardenRequestFulfilmentMetricName    = "warden_keychain_request_fulfilment_seconds"
+	wardenKeychainInfoMetricName         = "warden_keychain_info"
+	wardenKeychainAdminsMetricName       = "warden_keychain_admins"
+	wardenKeychainWritersMetricName      = "warden_keychain_writers"
+	wardenKeychainFeeMetricName          = "warden_keychain_fee"
+	wardenActionsMetricName              = "warden_actions"
+	wardenOldestPendingActionMetricName  = "warden_oldest_pending_action_age_seconds"
+	actionStatusPrefix                   = "ACTION_STATUS_"
+	keyRequestType                       = "key"
+	signRequestType                      = "sign"
+	keyRequestStatusPrefix               = "KEY_REQUEST_STATUS_"
+	signRequestStatusPrefix              = "SIGN_REQUEST_STATUS_"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	keyRequestStatuses = []warden.KeyRequestStatus{
+		warden.KeyRequestStatus_KEY_REQUEST_STATUS_PENDING,
+		warden.KeyRequestStatus_KEY_REQUEST_STATUS_FULFILLED,
+		warden.KeyRequestStatus_KEY_REQUEST_STATUS_REJECTED,
+	}
+
+	signRequestStatuses = []warden.SignRequestStatus{
+		warden.SignRequestStatus_SIGN_REQUEST_STATUS_PENDING,
+		warden.SignRequestStatus_SIGN_REQUEST_STATUS_FULFILLED,
+		warden.SignRequestStatus_SIGN_REQUEST_STATUS_REJECTED,
+	}
+
+	actionStatuses = []act.ActionStatus{
+		act.ActionStatus_ACTION_STATUS_PENDING,
+		act.ActionStatus_ACTION_STATUS_COMPLETED,
+		act.ActionStatus_ACTION_STATUS_REVOKED,
+		act.ActionStatus_ACTION_STATUS_TIMEOUT,
+	}
+
+	wardenKeyRequests = prometheus.NewDesc(
+		wardenKeyRequestsMetricName,
+		"Returns the number of key requests of the keychain by request status",
+		[]string{
+			"chain_id",
+			"keychain_id",
+			"request_status",
+			"status",
+		},
+		nil,
+	)
+
+	wardenSignRequests = prometheus.NewDesc(
+		wardenSignRequestsMetricName,
+		"Returns the number of sign requests of the keychain by request status",
+		[]string{
+			"chain_id",
+			"keychain_id",
+			"request_status",
+			"status",
+		},
+		nil,
+	)
+
+	wardenOldestPendingRequest = prometheus.NewDesc(
+		wardenOldestPendingRequestMetricName,
+		"Returns the age in seconds of the oldest pending request of the keychain, measured from the first scrape "+
+			"that saw it pending",
+		[]string{
+			"chain_id",
+			"keychain_id",
+			"request_type",
+			"status",
+		},
+		nil,
+	)
+
+	wardenKeychainInfo = prometheus.NewDesc(
+		wardenKeychainInfoMetricName,
+		"Returns 1 with the name and creator of the keychain",
+		[]string{
+			"chain_id",
+			"keychain_id",
+			"name",
+			"creator",
+		},
+		nil,
+	)
+
+	wardenKeychainAdmins = prometheus.NewDesc(
+		wardenKeychainAdminsMetricName,
+		"Returns the number of admins of the keychain",
+		[]string{
+			"chain_id",
+			"keychain_id",
+		},
+		nil,
+	)
+
+	wardenKeychainWriters = prometheus.NewDesc(
+		wardenKeychainWritersMetricName,
+		"Returns the number of writers allowed to fulfil requests of the keychain",
+		[]string{
+			"chain_id",
+			"keychain_id",
+		},
+		nil,
+	)
+
+	wardenKeychainFee = prometheus.NewDesc(
+		wardenKeychainFeeMetricName,
+		"Returns the fee the keychain charges per key or sign request, in whole tokens for the native denom and "+
+			"in base units for other denoms",
+		[]string{
+			"chain_id",
+			"keychain_id",
+			"request_type",
+			"denom",
+		},
+		nil,
+	)
+
+	wardenActions = prometheus.NewDesc(
+		wardenActionsMetricName,
+		"Returns the number of x/act actions by action status and message type",
+		[]string{
+			"chain_id",
+			"action_status",
+			"msg_type",
+			"status",
+		},
+		nil,
+	)
+
+	wardenOldestPendingAction = prometheus.NewDesc(
+		wardenOldestPendingActionMetricName,
+		"Returns the age in seconds of the oldest pending x/act action",
+		[]string{
+			"chain_id",
+			"status",
+		},
+		nil,
+	)
+
+	wardenRequestFulfilment = prometheus.NewDesc(
+		wardenRequestFulfilmentMetricName,
+		"Returns the distribution of the time between the first scrape that saw a request pending and the first "+
+			"scrape that saw it fulfilled",
+		[]string{
+			"chain_id",
+			"keychain_id",
+			"request_type",
+		},
+		nil,
+	)
+)
+
+// WardenCollector exports metrics of the Warden x/warden module.
+type WardenCollector struct {
+	Cfg      config.Config
+	Requests *RequestTracker
+	Keys     *KeyCounter
+	Actions  *ActionCounter
+	// SpaceIDs are the spaces to export key types and templates for, used
+	// with WARDEN_SPACE_METRICS.
+	SpaceIDs []uint64
+}
+
+func (w WardenCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- wardenKeyRequests
+	ch <- wardenSignRequests
+	ch <- wardenOldestPendingRequest
+	ch <- wardenRequestFulfilment
+	ch <- wardenKeychainInfo
+	ch <- wardenKeychainAdmins
+	ch <- wardenKeychainWriters
+	ch <- wardenKeychainFee
+	ch <- wardenActions
+	ch <- wardenOldestPendingAction
+	ch <- wardenSpaceKeys
+	ch <- wardenSpacesByOwners
+	ch <- wardenSpaceKeysByType
+	ch <- wardenSpaceTemplate
+	ch <- wardenKeys
+	ch <- wardenKeyCountSynced
+	ch <- wardenKeyCountCheckpoint
+	ch <- wardenKeyCountDuration
+	ch <- wardenKeyCountPages
+	ch <- wardenKeyCountPagesTotal
+	ch <- wardenKeyCountLastSuccess
+}
+
+func (w WardenCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(w.Cfg.Timeout)*time.Second,
+	)
+	defer cancel()
+
+	client, err := grpc.NewClient(w.Cfg)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting Warden metrics: %s", err))
+		return
+	}
+
+	defer func() {
+		if tempErr := client.CloseConn(); tempErr != nil {
+			log.Error(tempErr.Error())
+		}
+	}()
+
+	var errors []string
+
+	keychains, err := client.AllKeychains(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting keychains: %s", err))
+		errors = append(errors, "keychains")
+	}
+
+	for _, keychain := range keychains {
+		w.collectKeychainMetrics(ch, keychain)
+		errors = w.collectKeyRequestMetrics(ctx, ch, client, keychain.Id, errors)
+		errors = w.collectSignRequestMetrics(ctx, ch, client, keychain.Id, errors)
+	}
+
+	errors = w.collectActionMetrics(ctx, ch, client, errors)
+
+	w.collectKeyCountMetrics(ch)
+
+	if w.Cfg.WardenSpaceMetrics {
+		errors = w.collectSpaceMetrics(ctx, ch, client, errors)
+	}
+
+	if len(errors) > 0 {
+		log.Info(fmt.Sprintf("Warden metrics collection completed with errors: %v", errors))
+	} else {
+		log.Info("Warden metrics collection completed successfully")
+	}
+}
+
+func (w WardenCollector) collectKeychainMetrics(ch chan<- prometheus.Metric, keychain warden.Keychain) {
+	keychainID := strconv.FormatUint(keychain.Id, 10)
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeychainInfo,
+		prometheus.GaugeValue,
+		1,
+		[]string{w.Cfg.ChainID, keychainID, keychain.Name, keychain.Creator}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeychainAdmins,
+		prometheus.GaugeValue,
+		float64(len(keychain.Admins)),
+		[]string{w.Cfg.ChainID, keychainID}...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenKeychainWriters,
+		prometheus.GaugeValue,
+		float64(len(keychain.Writers)),
+		[]string{w.Cfg.ChainID, keychainID}...,
+	)
+
+	w.collectKeychainFees(ch, keychainID, keyRequestType, keychain.Fees.KeyReq)
+	w.collectKeychainFees(ch, keychainID, signRequestType, keychain.Fees.SigReq)
+}
+
+// collectKeychainFees exports the fee of a request type, a keychain without a
+// fee is exported with a 0 fee in the native denom.
+func (w WardenCollector) collectKeychainFees(
+	ch chan<- prometheus.Metric,
+	keychainID string,
+	requestType string,
+	fees sdk.Coins,
+) {
+	if fees.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			wardenKeychainFee,
+			prometheus.GaugeValue,
+			0,
+			[]string{w.Cfg.ChainID, keychainID, requestType, w.Cfg.Denom}...,
+		)
+
+		return
+	}
+
+	for _, fee := range fees {
+		exponent := 0
+		if fee.Denom == w.Cfg.Denom {
+			exponent = w.Cfg.Exponent
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			wardenKeychainFee,
+			prometheus.GaugeValue,
+			denomAmount(fee.Amount, exponent),
+			[]string{w.Cfg.ChainID, keychainID, requestType, fee.Denom}...,
+		)
+	}
+}
+
+// requestStatus is the status of a key or sign request.
+type requestStatus interface {
+	comparable
+	String() string
+}
+
+// requestQueries are the statuses and queries of a request type of a
+// keychain, shared by key and sign requests.
+type requestQueries[S requestStatus] struct {
+	requestType  string
+	desc         *prometheus.Desc
+	statusPrefix string
+	statuses     []S
+	pending      S
+	fulfilled    S
+	// pendingIDs returns the ids of the pending requests.
+	pendingIDs func() ([]uint64, error)
+	// count returns the number of requests with a status.
+	count func(status S) (uint64, error)
+	// status returns the status of a request.
+	status func(id uint64) (S, error)
+}
+
+func (w WardenCollector) collectKeyRequestMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	keychainID uint64,
+	errors []string,
+) []string {
+	return collectRequestMetrics(w, ch, keychainID, errors, requestQueries[warden.KeyRequestStatus]{
+		requestType:  keyRequestType,
+		desc:         wardenKeyRequests,
+		statusPrefix: keyRequestStatusPrefix,
+		statuses:     keyRequestStatuses,
+		pending:      warden.KeyRequestStatus_KEY_REQUEST_STATUS_PENDING,
+		fulfilled:    warden.KeyRequestStatus_KEY_REQUEST_STATUS_FULFILLED,
+		pendingIDs: func() ([]uint64, error) {
+			requests, err := client.PendingKeyRequests(ctx, keychainID)
+			ids := make([]uint64, 0, len(requests))
+			for _, request := range requests {
+				ids = append(ids, request.Id)
+			}
+
+			return ids, err
+		},
+		count: func(status warden.KeyRequestStatus) (uint64, error) {
+			return client.KeyRequestCount(ctx, keychainID, status)
+		},
+		status: func(id uint64) (warden.KeyRequestStatus, error) {
+			return client.KeyRequestStatus(ctx, id)
+		},
+	})
+}
+
+func (w WardenCollector) collectSignRequestMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	keychainID uint64,
+	errors []string,
+) []string {
+	return collectRequestMetrics(w, ch, keychainID, errors, requestQueries[warden.SignRequestStatus]{
+		requestType:  signRequestType,
+		desc:         wardenSignRequests,
+		statusPrefix: signRequestStatusPrefix,
+		statuses:     signRequestStatuses,
+		pending:      warden.SignRequestStatus_SIGN_REQUEST_STATUS_PENDING,
+		fulfilled:    warden.SignRequestStatus_SIGN_REQUEST_STATUS_FULFILLED,
+		pendingIDs: func() ([]uint64, error) {
+			requests, err := client.PendingSignRequests(ctx, keychainID)
+			ids := make([]uint64, 0, len(requests))
+			for _, request := range requests {
+				ids = append(ids, request.Id)
+			}
+
+			return ids, err
+		},
+		count: func(status warden.SignRequestStatus) (uint64, error) {
+			return client.SignRequestCount(ctx, keychainID, status)
+		},
+		status: func(id uint64) (warden.SignRequestStatus, error) {
+			return client.SignRequestStatus(ctx, id)
+		},
+	})
+}
+
+// collectRequestMetrics exports the request counts by status and the pending
+// request age of a keychain and request type.
+func collectRequestMetrics[S requestStatus](
+	w WardenCollector,
+	ch chan<- prometheus.Metric,
+	keychainID uint64,
+	errors []string,
+	queries requestQueries[S],
+) []string {
+	keychain := strconv.FormatUint(keychainID, 10)
+	status := successStatus
+
+	// Only pending requests are fetched, the other statuses are counted
+	pending, err := queries.pendingIDs()
+	counts := map[S]uint64{queries.pending: uint64(len(pending))}
+	for _, requestStatus := range queries.statuses {
+		if err != nil {
+			break
+		}
+		if requestStatus != queries.pending {
+			counts[requestStatus], err = queries.count(requestStatus)
+		}
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting %s requests of keychain %d: %s", queries.requestType, keychainID, err))
+		errors = append(errors, queries.requestType+" requests "+keychain)
+		status = errorStatus
+		counts = map[S]uint64{}
+	}
+
+	for _, requestStatus := range queries.statuses {
+		ch <- prometheus.MustNewConstMetric(
+			queries.desc,
+			prometheus.GaugeValue,
+			float64(counts[requestStatus]),
+			[]string{
+				w.Cfg.ChainID,
+				keychain,
+				requestStatusLabel(requestStatus.String(), queries.statusPrefix),
+				status,
+			}...,
+		)
+	}
+
+	if status == errorStatus {
+		ch <- prometheus.MustNewConstMetric(
+			wardenOldestPendingRequest,
+			prometheus.GaugeValue,
+			0,
+			[]string{w.Cfg.ChainID, keychain, queries.requestType, errorStatus}...,
+		)
+
+		return errors
+	}
+
+	group := requestGroup{keychainID: keychainID, requestType: queries.requestType}
+
+	var fulfilled []uint64
+	for _, id := range w.Requests.resolved(group, pending) {
+		requestStatus, statusErr := queries.status(id)
+		if statusErr != nil {
+			log.Debug(fmt.Sprintf("Error getting %s request %d: %s", queries.requestType, id, statusErr))
+			continue
+		}
+		if requestStatus == queries.fulfilled {
+			fulfilled = append(fulfilled, id)
+		}
+	}
+
+	w.collectRequestAge(ch, group, pending, fulfilled)
+
+	return errors
+}
+
+func (w WardenCollector) collectActionMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	errors []string,
+) []string {
+	if err := w.Actions.update(ctx, client); err != nil {
+		log.Error(fmt.Sprintf("error getting actions: %s", err))
+
+		for _, actionStatus := range actionStatuses {
+			ch <- prometheus.MustNewConstMetric(
+				wardenActions,
+				prometheus.GaugeValue,
+				0,
+				[]string{
+					w.Cfg.ChainID,
+					requestStatusLabel(actionStatus.String(), actionStatusPrefix),
+					unknownGroup,
+					errorStatus,
+				}...,
+			)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			wardenOldestPendingAction,
+			prometheus.GaugeValue,
+			0,
+			[]string{w.Cfg.ChainID, errorStatus}...,
+		)
+
+		return append(errors, "actions")
+	}
+
+	counts, oldest := w.Actions.counts()
+
+	// Every status is exported for every message type so a pending count
+	// dropping to 0 is a series rather than a missing one
+	for msgType, statusCounts := range counts {
+		for _, actionStatus := range actionStatuses {
+			ch <- prometheus.MustNewConstMetric(
+				wardenActions,
+				prometheus.GaugeValue,
+				float64(statusCounts[actionStatus]),
+				[]string{
+					w.Cfg.ChainID,
+					requestStatusLabel(actionStatus.String(), actionStatusPrefix),
+					msgType,
+					successStatus,
+				}...,
+			)
+		}
+	}
+
+	oldestAge := 0.0
+	if !oldest.IsZero() {
+		oldestAge = max(time.Since(oldest).Seconds(), 0)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenOldestPendingAction,
+		prometheus.GaugeValue,
+		oldestAge,
+		[]string{w.Cfg.ChainID, successStatus}...,
+	)
+
+	return errors
+}
+
+// collectRequestAge exports the oldest pending request age and the fulfilment
+// latency histogram of a keychain and request type.
+func (w WardenCollector) collectRequestAge(
+	ch chan<- prometheus.Metric,
+	group requestGroup,
+	pending []uint64,
+	fulfilled []uint64,
+) {
+	keychain := strconv.FormatUint(group.keychainID, 10)
+
+	oldest := w.Requests.observe(group, pending, fulfilled, time.Now())
+
+	ch <- prometheus.MustNewConstMetric(
+		wardenOldestPendingRequest,
+		prometheus.GaugeValue,
+		oldest,
+		[]string{w.Cfg.ChainID, keychain, group.requestType, successStatus}...,
+	)
+
+	count, sum, buckets := w.Requests.latency(group)
+
+	ch <- prometheus.MustNewConstHistogram(
+		wardenRequestFulfilment,
+		count,
+		sum,
+		buckets,
+		[]string{w.Cfg.ChainID, keychain, group.requestType}...,
+	)
+}
+
+// requestStatusLabel turns a request or action status such as
+// SIGN_REQUEST_STATUS_PENDING into a label value such as pending.
+func requestStatusLabel(status string, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(status, prefix))
+}
@@ -0,0 +1,481 @@
+package collector
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	act "github.com/warden-protocol/wardenprotocol/warden/x/act/types/v1beta1"
+	warden "github.com/warden-protocol/wardenprotocol/warden/x/warden/types/v1beta3"
+	googlegrpc "google.golang.org/grpc"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+)
+
+type keychainCollector struct {
+	w        WardenCollector
+	keychain warden.Keychain
+}
+
+func (k keychainCollector) Describe(ch chan<- *prometheus.Desc) {
+	k.w.Describe(ch)
+}
+
+func (k keychainCollector) Collect(ch chan<- prometheus.Metric) {
+	k.w.collectKeychainMetrics(ch, k.keychain)
+}
+
+// TestCollectKeychainMetrics tests that keychain fees in the native denom are
+// converted to whole tokens and that a keychain without fees gets a 0 fee.
+func TestCollectKeychainMetrics(t *testing.T) {
+	c := keychainCollector{
+		w: WardenCollector{Cfg: config.Config{ChainID: "warden_8765-1", Denom: "award", Exponent: 18}},
+		keychain: warden.Keychain{
+			Id:      3,
+			Name:    "fordefi",
+			Admins:  []string{"warden1a"},
+			Writers: []string{"warden1b", "warden1c"},
+			Fees: warden.KeychainFees{
+				SigReq: sdk.NewCoins(sdk.NewCoin("award", sdkmath.NewIntWithDecimal(5, 17))),
+			},
+		},
+	}
+
+	metrics := collectGauges(t, c)
+
+	if got := metrics[wardenKeychainWriters][0].GetGauge().GetValue(); got != 2 {
+		t.Errorf("expected 2 writers, got %v", got)
+	}
+
+	fees := map[string]float64{}
+	for _, m := range metrics[wardenKeychainFee] {
+		labels := metricLabels(m)
+		if labels["denom"] != "award" {
+			t.Errorf("expected denom award, got %s", labels["denom"])
+		}
+		fees[labels["request_type"]] = m.GetGauge().GetValue()
+	}
+
+	if fees[keyRequestType] != 0 || fees[signRequestType] != 0.5 {
+		t.Errorf("unexpected fees %v", fees)
+	}
+}
+
+// TestSpaceKeysHistogram tests that spaces without keys are counted in the
+// keys per space histogram.
+func TestSpaceKeysHistogram(t *testing.T) {
+	spaces := []warden.Space{{Id: 1}, {Id: 2}, {Id: 3}}
+	keysPerSpace := map[uint64]uint64{1: 3, 3: 1}
+
+	count, sum, buckets := spaceKeysHistogram(spaces, keysPerSpace)
+
+	if count != 3 || sum != 4 {
+		t.Errorf("expected count 3 and sum 4, got %d and %v", count, sum)
+	}
+
+	if buckets[0] != 1 || buckets[1] != 2 || buckets[2] != 2 || buckets[5] != 3 {
+		t.Errorf("unexpected buckets %v", buckets)
+	}
+}
+
+// requestsServer serves the key and sign requests of keychain 1. It fails the
+// test when requests other than pending ones are paged through.
+type requestsServer struct {
+	warden.UnimplementedQueryServer
+	t            *testing.T
+	mu           sync.Mutex
+	keyRequests  map[uint64]warden.KeyRequestStatus
+	signRequests map[uint64]warden.SignRequestStatus
+}
+
+// requestsPage returns the ids with the given status, or only their number
+// when the page request counts the total.
+func requestsPage[S comparable](
+	t *testing.T,
+	requests map[uint64]S,
+	status, pendingStatus S,
+	page *query.PageRequest,
+) ([]uint64, *query.PageResponse) {
+	if status != pendingStatus && (!page.GetCountTotal() || page.GetLimit() != 1) {
+		t.Errorf("requests with status %v are paged through", status)
+	}
+
+	ids := []uint64{}
+	for id, requestStatus := range requests {
+		if requestStatus == status {
+			ids = append(ids, id)
+		}
+	}
+
+	res := &query.PageResponse{}
+	if page.GetCountTotal() {
+		res.Total = uint64(len(ids))
+		ids = ids[:min(len(ids), int(page.GetLimit()))]
+	}
+
+	return ids, res
+}
+
+func (s *requestsServer) KeyRequests(
+	_ context.Context,
+	req *warden.QueryKeyRequestsRequest,
+) (*warden.QueryKeyRequestsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, page := requestsPage(
+		s.t, s.keyRequests, req.GetStatus(), warden.KeyRequestStatus_KEY_REQUEST_STATUS_PENDING, req.GetPagination(),
+	)
+
+	res := &warden.QueryKeyRequestsResponse{Pagination: page}
+	for _, id := range ids {
+		res.KeyRequests = append(res.KeyRequests, &warden.KeyRequest{Id: id, KeychainId: 1, Status: req.GetStatus()})
+	}
+
+	return res, nil
+}
+
+func (s *requestsServer) KeyRequestById(
+	_ context.Context,
+	req *warden.QueryKeyRequestByIdRequest,
+) (*warden.QueryKeyRequestByIdResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &warden.QueryKeyRequestByIdResponse{
+		KeyRequest: &warden.KeyRequest{Id: req.GetId(), KeychainId: 1, Status: s.keyRequests[req.GetId()]},
+	}, nil
+}
+
+func (s *requestsServer) SignRequests(
+	_ context.Context,
+	req *warden.QuerySignRequestsRequest,
+) (*warden.QuerySignRequestsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, page := requestsPage(
+		s.t, s.signRequests, req.GetStatus(), warden.SignRequestStatus_SIGN_REQUEST_STATUS_PENDING, req.GetPagination(),
+	)
+
+	res := &warden.QuerySignRequestsResponse{Pagination: page}
+	for _, id := range ids {
+		res.SignRequests = append(res.SignRequests, &warden.SignRequest{Id: id, Status: req.GetStatus()})
+	}
+
+	return res, nil
+}
+
+func (s *requestsServer) SignRequestById(
+	_ context.Context,
+	req *warden.QuerySignRequestByIdRequest,
+) (*warden.QuerySignRequestByIdResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &warden.QuerySignRequestByIdResponse{
+		SignRequest: &warden.SignRequest{Id: req.GetId(), Status: s.signRequests[req.GetId()]},
+	}, nil
+}
+
+func (s *requestsServer) setSignRequest(id uint64, status warden.SignRequestStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.signRequests[id] = status
+}
+
+type requestsCollector struct {
+	w      WardenCollector
+	client grpc.Client
+}
+
+func (r requestsCollector) Describe(ch chan<- *prometheus.Desc) {
+	r.w.Describe(ch)
+}
+
+func (r requestsCollector) Collect(ch chan<- prometheus.Metric) {
+	r.w.collectKeyRequestMetrics(context.Background(), ch, r.client, 1, nil)
+	r.w.collectSignRequestMetrics(context.Background(), ch, r.client, 1, nil)
+}
+
+// TestCollectRequestMetrics tests that request counts are read from the
+// pending requests and the totals of the other statuses, and that requests
+// leaving the pending state are looked up to record fulfilment latencies.
+func TestCollectRequestMetrics(t *testing.T) {
+	requests := &requestsServer{
+		t: t,
+		keyRequests: map[uint64]warden.KeyRequestStatus{
+			1: warden.KeyRequestStatus_KEY_REQUEST_STATUS_FULFILLED,
+			2: warden.KeyRequestStatus_KEY_REQUEST_STATUS_FULFILLED,
+			3: warden.KeyRequestStatus_KEY_REQUEST_STATUS_REJECTED,
+			4: warden.KeyRequestStatus_KEY_REQUEST_STATUS_PENDING,
+		},
+		signRequests: map[uint64]warden.SignRequestStatus{
+			1: warden.SignRequestStatus_SIGN_REQUEST_STATUS_FULFILLED,
+			2: warden.SignRequestStatus_SIGN_REQUEST_STATUS_PENDING,
+		},
+	}
+
+	cfg := newTestServer(t, func(server *googlegrpc.Server) {
+		warden.RegisterQueryServer(server, requests)
+	})
+	client := newTestClient(t, cfg)
+
+	c := requestsCollector{w: WardenCollector{Cfg: cfg, Requests: NewRequestTracker()}, client: client}
+
+	metrics := collectGauges(t, c)
+
+	keyCounts := map[string]float64{}
+	for _, m := range metrics[wardenKeyRequests] {
+		keyCounts[metricLabels(m)["request_status"]] = m.GetGauge().GetValue()
+	}
+	if keyCounts["pending"] != 1 || keyCounts["fulfilled"] != 2 || keyCounts["rejected"] != 1 {
+		t.Errorf("key request counts = %v, want pending 1, fulfilled 2, rejected 1", keyCounts)
+	}
+
+	// Request 3 is first seen pending by the second scrape, then fulfilled,
+	// while request 2 is rejected
+	requests.setSignRequest(3, warden.SignRequestStatus_SIGN_REQUEST_STATUS_PENDING)
+	collectGauges(t, c)
+	requests.setSignRequest(2, warden.SignRequestStatus_SIGN_REQUEST_STATUS_REJECTED)
+	requests.setSignRequest(3, warden.SignRequestStatus_SIGN_REQUEST_STATUS_FULFILLED)
+	metrics = collectGauges(t, c)
+
+	signCounts := map[string]float64{}
+	for _, m := range metrics[wardenSignRequests] {
+		signCounts[metricLabels(m)["request_status"]] = m.GetGauge().GetValue()
+	}
+	if signCounts["pending"] != 0 || signCounts["fulfilled"] != 2 || signCounts["rejected"] != 1 {
+		t.Errorf("sign request counts = %v, want pending 0, fulfilled 2, rejected 1", signCounts)
+	}
+
+	for _, m := range metrics[wardenRequestFulfilment] {
+		want := uint64(0)
+		if metricLabels(m)["request_type"] == signRequestType {
+			want = 1
+		}
+		if got := m.GetHistogram().GetSampleCount(); got != want {
+			t.Errorf("%s fulfilment latencies = %d, want %d", metricLabels(m)["request_type"], got, want)
+		}
+	}
+}
+
+type actionsServer struct {
+	act.UnimplementedQueryServer
+	mu      sync.Mutex
+	actions []act.Action
+	err     error
+	// starts are the ids the action pages were requested from.
+	starts []uint64
+}
+
+func (s *actionsServer) Actions(
+	_ context.Context,
+	req *act.QueryActionsRequest,
+) (*act.QueryActionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	start := binary.BigEndian.Uint64(req.GetPagination().GetKey())
+	s.starts = append(s.starts, start)
+
+	res := &act.QueryActionsResponse{Pagination: &query.PageResponse{}}
+	for _, action := range s.actions {
+		if action.Id >= start {
+			res.Actions = append(res.Actions, action)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *actionsServer) ActionById(
+	_ context.Context,
+	req *act.QueryActionByIdRequest,
+) (*act.QueryActionByIdResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, action := range s.actions {
+		if action.Id == req.GetId() {
+			return &act.QueryActionByIdResponse{Action: &action}, nil
+		}
+	}
+
+	return nil, errors.New("action not found")
+}
+
+func (s *actionsServer) setAction(action act.Action) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.actions {
+		if s.actions[i].Id == action.Id {
+			s.actions[i] = action
+			return
+		}
+	}
+	s.actions = append(s.actions, action)
+}
+
+type actionsCollector struct {
+	w      WardenCollector
+	client grpc.Client
+}
+
+func (a actionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	a.w.Describe(ch)
+}
+
+func (a actionsCollector) Collect(ch chan<- prometheus.Metric) {
+	a.w.collectActionMetrics(context.Background(), ch, a.client, nil)
+}
+
+func actionCounts(t *testing.T, metrics map[*prometheus.Desc][]*dto.Metric) map[[2]string]float64 {
+	t.Helper()
+
+	counts := map[[2]string]float64{}
+	for _, m := range metrics[wardenActions] {
+		labels := metricLabels(m)
+		if labels["status"] != successStatus {
+			t.Errorf("unexpected status %q", labels["status"])
+		}
+		counts[[2]string{labels["msg_type"], labels["action_status"]}] = m.GetGauge().GetValue()
+	}
+
+	return counts
+}
+
+// TestCollectActionMetrics tests that actions are counted by status and
+// message type, with actions without a message counted as unknown, that the
+// oldest pending action age ignores actions that are no longer pending and
+// that later scrapes only fetch new actions and look up pending ones.
+func TestCollectActionMetrics(t *testing.T) {
+	now := time.Now()
+	send := &codectypes.Any{TypeUrl: "/cosmos.bank.v1beta1.MsgSend"}
+	actions := &actionsServer{actions: []act.Action{
+		{Id: 1, Status: act.ActionStatus_ACTION_STATUS_PENDING, Msg: send, CreatedAt: now.Add(-10 * time.Minute)},
+		{Id: 2, Status: act.ActionStatus_ACTION_STATUS_PENDING, Msg: send, CreatedAt: now.Add(-time.Minute)},
+		{Id: 3, Status: act.ActionStatus_ACTION_STATUS_COMPLETED, Msg: send, CreatedAt: now.Add(-time.Hour)},
+		{Id: 4, Status: act.ActionStatus_ACTION_STATUS_REVOKED, CreatedAt: now.Add(-time.Hour)},
+	}}
+
+	cfg := newTestServer(t, func(server *googlegrpc.Server) {
+		act.RegisterQueryServer(server, actions)
+	})
+	client := newTestClient(t, cfg)
+
+	c := actionsCollector{w: WardenCollector{Cfg: cfg, Actions: NewActionCounter()}, client: client}
+	metrics := collectGauges(t, c)
+	counts := actionCounts(t, metrics)
+
+	// Every status is exported for both message types
+	if len(counts) != 2*len(actionStatuses) {
+		t.Errorf("got %d action series, want %d", len(counts), 2*len(actionStatuses))
+	}
+
+	expected := map[[2]string]float64{
+		{send.TypeUrl, "pending"}:   2,
+		{send.TypeUrl, "completed"}: 1,
+		{send.TypeUrl, "revoked"}:   0,
+		{unknownGroup, "revoked"}:   1,
+		{unknownGroup, "pending"}:   0,
+	}
+	for group, want := range expected {
+		if counts[group] != want {
+			t.Errorf("actions%v = %v, want %v", group, counts[group], want)
+		}
+	}
+
+	oldest := metrics[wardenOldestPendingAction][0].GetGauge().GetValue()
+	if oldest < 600 || oldest > 660 {
+		t.Errorf("oldest pending action age = %v, want about 600", oldest)
+	}
+
+	// The oldest pending action times out and a new one is created
+	actions.setAction(act.Action{
+		Id: 1, Status: act.ActionStatus_ACTION_STATUS_TIMEOUT, Msg: send, CreatedAt: now.Add(-10 * time.Minute),
+	})
+	actions.setAction(act.Action{
+		Id: 5, Status: act.ActionStatus_ACTION_STATUS_PENDING, Msg: send, CreatedAt: now.Add(-5 * time.Minute),
+	})
+
+	metrics = collectGauges(t, c)
+	counts = actionCounts(t, metrics)
+
+	expected = map[[2]string]float64{
+		{send.TypeUrl, "pending"}:   2,
+		{send.TypeUrl, "completed"}: 1,
+		{send.TypeUrl, "timeout"}:   1,
+		{unknownGroup, "revoked"}:   1,
+	}
+	for group, want := range expected {
+		if counts[group] != want {
+			t.Errorf("actions%v = %v, want %v after the second scrape", group, counts[group], want)
+		}
+	}
+
+	oldest = metrics[wardenOldestPendingAction][0].GetGauge().GetValue()
+	if oldest < 300 || oldest > 360 {
+		t.Errorf("oldest pending action age = %v, want about 300", oldest)
+	}
+
+	if len(actions.starts) != 2 || actions.starts[0] != 0 || actions.starts[1] != 5 {
+		t.Errorf("actions were paged from %v, want [0 5]", actions.starts)
+	}
+}
+
+// TestCollectActionMetricsError tests that a failing query exports every
+// status with an error status and no oldest pending action age.
+func TestCollectActionMetricsError(t *testing.T) {
+	actions := &actionsServer{err: errors.New("unavailable")}
+
+	cfg := newTestServer(t, func(server *googlegrpc.Server) {
+		act.RegisterQueryServer(server, actions)
+	})
+	client := newTestClient(t, cfg)
+
+	w := WardenCollector{Cfg: cfg, Actions: NewActionCounter()}
+
+	ch := make(chan prometheus.Metric)
+	var errs []string
+	go func() {
+		errs = w.collectActionMetrics(context.Background(), ch, client, nil)
+		close(ch)
+	}()
+
+	series := 0
+	for m := range ch {
+		metric := &dto.Metric{}
+		if err := m.Write(metric); err != nil {
+			t.Fatalf("error writing metric: %s", err)
+		}
+		labels := metricLabels(metric)
+		if labels["status"] != errorStatus || metric.GetGauge().GetValue() != 0 {
+			t.Errorf("unexpected series %v = %v", labels, metric.GetGauge().GetValue())
+		}
+		series++
+	}
+
+	if series != len(actionStatuses)+1 {
+		t.Errorf("got %d series, want %d", series, len(actionStatuses)+1)
+	}
+	if len(errs) != 1 || errs[0] != "actions" {
+		t.Errorf("errors = %v, want [actions]", errs)
+	}
+}
@@ -0,0 +1,230 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	warden "github.com/warden-protocol/wardenprotocol/warden/x/warden/types/v1beta3"
+
+	"github.com/warden-protocol/warden-exporter/pkg/grpc"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const (
+	wardenSpaceKeysMetricName       = "warden_space_keys"
+	wardenSpacesByOwnersMetricName  = "warden_spaces_by_owners"
+	wardenSpaceKeysByTypeMetricName = "warden_space_keys_by_type"
+	wardenSpaceTemplateMetricName   = "warden_space_template_info"
+	keyTypePrefix                   = "KEY_TYPE_"
+)
+
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	spaceKeysBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100, 500, 1000}
+
+	keyTypes = []warden.KeyType{
+		warden.KeyType_KEY_TYPE_ECDSA_SECP256K1,
+		warden.KeyType_KEY_TYPE_EDDSA_ED25519,
+		warden.KeyType_KEY_TYPE_UNSPECIFIED,
+	}
+
+	wardenSpaceKeys = prometheus.NewDesc(
+		wardenSpaceKeysMetricName,
+		"Returns the distribution of the number of keys per space",
+		[]string{
+			"chain_id",
+		},
+		nil,
+	)
+
+	wardenSpacesByOwners = prometheus.NewDesc(
+		wardenSpacesByOwnersMetricName,
+		"Returns the number of spaces by number of owners",
+		[]string{
+			"chain_id",
+			"owners",
+		},
+		nil,
+	)
+
+	wardenSpaceKeysByType = prometheus.NewDesc(
+		wardenSpaceKeysByTypeMetricName,
+		"Returns the number of keys of the space by key type",
+		[]string{
+			"chain_id",
+			"space_id",
+			"key_type",
+			"status",
+		},
+		nil,
+	)
+
+	wardenSpaceTemplate = prometheus.NewDesc(
+		wardenSpaceTemplateMetricName,
+		"Returns 1 with the id and name of each approval template of the space",
+		[]string{
+			"chain_id",
+			"space_id",
+			"template_type",
+			"template_id",
+			"template_name",
+		},
+		nil,
+	)
+)
+
+func (w WardenCollector) collectSpaceMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	errors []string,
+) []string {
+	spaces, err := client.AllSpaces(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting spaces: %s", err))
+		return append(errors, "spaces")
+	}
+
+	owners := map[int]int{}
+	for _, space := range spaces {
+		owners[len(space.Owners)]++
+	}
+
+	for ownerCount, spaceCount := range owners {
+		ch <- prometheus.MustNewConstMetric(
+			wardenSpacesByOwners,
+			prometheus.GaugeValue,
+			float64(spaceCount),
+			[]string{w.Cfg.ChainID, strconv.Itoa(ownerCount)}...,
+		)
+	}
+
+	// The histogram is left out until the key count has seen every key
+	if keysPerSpace, synced := w.Keys.keysPerSpace(); synced {
+		count, sum, buckets := spaceKeysHistogram(spaces, keysPerSpace)
+
+		ch <- prometheus.MustNewConstHistogram(
+			wardenSpaceKeys,
+			count,
+			sum,
+			buckets,
+			[]string{w.Cfg.ChainID}...,
+		)
+	}
+
+	byID := make(map[uint64]warden.Space, len(spaces))
+	for _, space := range spaces {
+		byID[space.Id] = space
+	}
+
+	for _, spaceID := range w.SpaceIDs {
+		space, ok := byID[spaceID]
+		if !ok {
+			log.Error(fmt.Sprintf("space %d not found", spaceID))
+			errors = append(errors, fmt.Sprintf("space %d", spaceID))
+			continue
+		}
+
+		errors = w.collectSpaceDetails(ctx, ch, client, space, errors)
+	}
+
+	return errors
+}
+
+// collectSpaceDetails exports the key types and approval templates of a space.
+func (w WardenCollector) collectSpaceDetails(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	client grpc.Client,
+	space warden.Space,
+	errors []string,
+) []string {
+	spaceID := strconv.FormatUint(space.Id, 10)
+	status := successStatus
+
+	keys, err := client.SpaceKeys(ctx, space.Id)
+	if err != nil {
+		log.Error(fmt.Sprintf("error getting keys of space %d: %s", space.Id, err))
+		errors = append(errors, "keys of space "+spaceID)
+		status = errorStatus
+	}
+
+	counts := map[warden.KeyType]int{}
+	for _, key := range keys {
+		counts[key.Type]++
+	}
+
+	for _, keyType := range keyTypes {
+		ch <- prometheus.MustNewConstMetric(
+			wardenSpaceKeysByType,
+			prometheus.GaugeValue,
+			float64(counts[keyType]),
+			[]string{
+				w.Cfg.ChainID,
+				spaceID,
+				keyTypeLabel(keyType),
+				status,
+			}...,
+		)
+	}
+
+	templates := []struct {
+		templateType string
+		id           uint64
+	}{
+		{"approve_admin", space.ApproveAdminTemplateId},
+		{"reject_admin", space.RejectAdminTemplateId},
+		{"approve_sign", space.ApproveSignTemplateId},
+		{"reject_sign", space.RejectSignTemplateId},
+	}
+
+	for _, t := range templates {
+		// 0 means the space uses the default template
+		if t.id == 0 {
+			continue
+		}
+
+		name := unknownGroup
+		template, templateErr := client.Template(ctx, t.id)
+		if templateErr != nil {
+			log.Error(fmt.Sprintf("error getting template %d: %s", t.id, templateErr))
+			errors = append(errors, fmt.Sprintf("template %d", t.id))
+		} else {
+			name = template.Name
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			wardenSpaceTemplate,
+			prometheus.GaugeValue,
+			1,
+			[]string{w.Cfg.ChainID, spaceID, t.templateType, strconv.FormatUint(t.id, 10), name}...,
+		)
+	}
+
+	return errors
+}
+
+// spaceKeysHistogram returns the count, sum and cumulative bucket counts of
+// the number of keys per space, including spaces without keys.
+func spaceKeysHistogram(spaces []warden.Space, keysPerSpace map[uint64]uint64) (uint64, float64, map[float64]uint64) {
+	buckets := make(map[float64]uint64, len(spaceKeysBuckets))
+	for _, bucket := range spaceKeysBuckets {
+		buckets[bucket] = 0
+	}
+
+	var sum float64
+	for _, space := range spaces {
+		keys := float64(keysPerSpace[space.Id])
+		sum += keys
+
+		for _, bucket := range spaceKeysBuckets {
+			if keys <= bucket {
+				buckets[bucket]++
+			}
+		}
+	}
+
+	return uint64(len(spaces)), sum, buckets
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,9 +20,35 @@ const (
 	xaiUsageMetricName         = "xai_usage"
 	xaiSpendingLimitMetricName = "xai_postpaid_spending_limit"
 	xaiBalanceMetricName       = "xai_prepaid_balance"
-	xaiAPIURL                  = "https://management-api.x.ai/v1"
+	xaiBurnRateMetricName      = "xai_prepaid_balance_burn_rate_per_hour"
+	xaiDepletionMetricName     = "xai_prepaid_balance_depletion_seconds"
+	xaiUsageByModelMetricName  = "xai_usage_by_model"
+	xaiRequestsMetricName      = "xai_requests"
+	xaiTokensMetricName        = "xai_tokens"
+	xaiAggregationSum          = "AGGREGATION_SUM"
 )
 
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	// xaiUsageGroupBy are the analytics dimensions usage is broken down by,
+	// in the order of the model and api_key labels.
+	xaiUsageGroupBy = []string{"model", "api_key_id"}
+
+	// xaiUsageValues are the analytics values requested for the usage
+	// breakdown. Cost comes first as it is used to rank groups.
+	xaiUsageValues = []XAIUsageValue{
+		{Name: "usd", Aggregation: xaiAggregationSum},
+		{Name: "num_requests", Aggregation: xaiAggregationSum},
+		{Name: "prompt_tokens", Aggregation: xaiAggregationSum},
+		{Name: "completion_tokens", Aggregation: xaiAggregationSum},
+	}
+)
+
+type XAIUsageValue struct {
+	Name        string `json:"name"`
+	Aggregation string `json:"aggregation"`
+}
+
 type XAIUsageRequest struct {
 	AnalyticsRequest struct {
 		TimeRange struct {
@@ -29,13 +56,10 @@ type XAIUsageRequest struct {
 			EndTime   string `json:"endTime"`
 			Timezone  string `json:"timezone"`
 		} `json:"timeRange"`
-		TimeUnit string `json:"timeUnit"`
-		Values   []struct {
-			Name        string `json:"name"`
-			Aggregation string `json:"aggregation"`
-		} `json:"values"`
-		GroupBy []string      `json:"groupBy"`
-		Filters []interface{} `json:"filters"`
+		TimeUnit string          `json:"timeUnit"`
+		Values   []XAIUsageValue `json:"values"`
+		GroupBy  []string        `json:"groupBy"`
+		Filters  []interface{}   `json:"filters"`
 	} `json:"analyticsRequest"`
 }
 
@@ -97,17 +121,60 @@ var (
 		xaiUsageMetricName,
 		"Returns X.AI API usage cost in USD",
 		[]string{
+			"account",
+			"period",
+			"currency",
+			"status",
+		},
+		nil,
+	)
+
+	xaiUsageByModel = prometheus.NewDesc(
+		xaiUsageByModelMetricName,
+		"Returns X.AI API usage cost in USD by model and API key",
+		[]string{
+			"account",
 			"period",
+			"model",
+			"api_key",
 			"currency",
 			"status",
 		},
 		nil,
 	)
 
+	xaiRequests = prometheus.NewDesc(
+		xaiRequestsMetricName,
+		"Returns X.AI API request count by model and API key",
+		[]string{
+			"account",
+			"period",
+			"model",
+			"api_key",
+			"status",
+		},
+		nil,
+	)
+
+	xaiTokens = prometheus.NewDesc(
+		xaiTokensMetricName,
+		"Returns X.AI API token count by model, API key and token type",
+		[]string{
+			"account",
+			"period",
+			"model",
+			"api_key",
+			"type",
+			"status",
+		},
+		nil,
+	)
+
 	xaiSpendingLimit = prometheus.NewDesc(
 		xaiSpendingLimitMetricName,
 		"Returns X.AI postpaid spending limits information",
 		[]string{
+			"account",
 			"limit_type",
 			"currency",
 			"status",
@@ -119,50 +186,137 @@ var (
 		xaiBalanceMetricName,
 		"Returns X.AI prepaid balance information",
 		[]string{
+			"account",
 			"currency",
 			"status",
 		},
 		nil,
 	)
+
+	xaiBurnRate = prometheus.NewDesc(
+		xaiBurnRateMetricName,
+		"Returns X.AI prepaid balance spent per hour over the given window",
+		[]string{
+			"account",
+			"currency",
+			"window",
+		},
+		nil,
+	)
+
+	xaiDepletion = prometheus.NewDesc(
+		xaiDepletionMetricName,
+		"Returns estimated seconds until the X.AI prepaid balance is depleted at the burn rate of the given window",
+		[]string{
+			"account",
+			"currency",
+			"window",
+		},
+		nil,
+	)
 )
 
+// xaiAccount is a configured X.AI management key and the team it manages.
+type xaiAccount struct {
+	config.APIAccount
+	teamID string
+}
+
 type XAICollector struct {
-	Cfg config.Config
+	Cfg     config.Config
+	History *BalanceHistory
 }
 
 func (x XAICollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- xaiUsage
+	ch <- xaiUsageByModel
+	ch <- xaiRequests
+	ch <- xaiTokens
 	ch <- xaiSpendingLimit
 	ch <- xaiBalance
+
+	if x.History != nil {
+		ch <- xaiBurnRate
+		ch <- xaiDepletion
+	}
 }
 
 func (x XAICollector) Collect(ch chan<- prometheus.Metric) {
+	x.collectFailures(ch)
+}
+
+// collectFailures reports the accounts with failed requests, some of which,
+// like the usage breakdown, leave no series behind.
+func (x XAICollector) collectFailures(ch chan<- prometheus.Metric) []string {
 	ctx, cancel := context.WithTimeout(
 		context.Background(),
 		time.Duration(x.Cfg.Timeout)*time.Second,
 	)
 	defer cancel()
 
+	var failed []string
+	for _, account := range x.accounts() {
+		if errors := x.collectAccount(ctx, ch, account); len(errors) > 0 {
+			failed = append(failed, account.Name)
+		}
+	}
+
+	return failed
+}
+
+// accounts pairs every XAI_API_KEY entry with the XAI_TEAM_ID entry at the
+// same position. A single team id is shared by all keys.
+func (x XAICollector) accounts() []xaiAccount {
+	keys := config.ParseAPIAccounts(x.Cfg.XAIAPIKey)
+	teamIDs := splitCommaList(x.Cfg.XAITeamID)
+
+	accounts := make([]xaiAccount, 0, len(keys))
+	for i, key := range keys {
+		account := xaiAccount{APIAccount: key}
+		switch {
+		case i < len(teamIDs):
+			account.teamID = teamIDs[i]
+		case len(teamIDs) == 1:
+			account.teamID = teamIDs[0]
+		default:
+			log.Error(fmt.Sprintf("no X.AI team id configured for account %s", key.Name))
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts
+}
+
+func (x XAICollector) collectAccount(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account xaiAccount,
+) []string {
 	var errors []string
 
-	errors = x.collectUsageMetrics(ctx, ch, errors)
-	errors = x.collectSpendingLimitMetrics(ctx, ch, errors)
-	errors = x.collectBalanceMetrics(ctx, ch, errors)
+	errors = x.collectUsageMetrics(ctx, ch, account, errors)
+	errors = x.collectUsageBreakdownMetrics(ctx, ch, account, errors)
+	errors = x.collectSpendingLimitMetrics(ctx, ch, account, errors)
+	errors = x.collectBalanceMetrics(ctx, ch, account, errors)
 
 	if len(errors) > 0 {
-		log.Info(fmt.Sprintf("X.AI metrics collection completed with errors: %v", errors))
+		log.Info(fmt.Sprintf("X.AI metrics collection for account %s completed with errors: %v", account.Name, errors))
 	} else {
-		log.Info("X.AI metrics collection completed successfully")
+		log.Info(fmt.Sprintf("X.AI metrics collection for account %s completed successfully", account.Name))
 	}
+
+	return errors
 }
 
 func (x XAICollector) collectUsageMetrics(
 	ctx context.Context,
 	ch chan<- prometheus.Metric,
+	account xaiAccount,
 	errors []string,
 ) []string {
 	monthlyUsageStatus := successStatus
-	monthlyUsage, err := x.xaiCollectUsageMonthly(ctx)
+	monthlyUsage, err := x.xaiCollectUsageMonthly(ctx, account)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting X.AI monthly usage: %s", err))
 		errors = append(errors, "monthly usage")
@@ -174,11 +328,11 @@ func (x XAICollector) collectUsageMetrics(
 		xaiUsage,
 		prometheus.GaugeValue,
 		monthlyUsage,
-		[]string{"monthly", "USD", monthlyUsageStatus}...,
+		[]string{account.Name, "monthly", "USD", monthlyUsageStatus}...,
 	)
 
 	dailyUsageStatus := successStatus
-	dailyUsage, errDaily := x.xaiCollectUsageDaily(ctx)
+	dailyUsage, errDaily := x.xaiCollectUsageDaily(ctx, account)
 	if errDaily != nil {
 		log.Error(fmt.Sprintf("error collecting X.AI daily usage: %s", errDaily))
 		errors = append(errors, "daily usage")
@@ -190,19 +344,80 @@ func (x XAICollector) collectUsageMetrics(
 		xaiUsage,
 		prometheus.GaugeValue,
 		dailyUsage,
-		[]string{"daily", "USD", dailyUsageStatus}...,
+		[]string{account.Name, "daily", "USD", dailyUsageStatus}...,
 	)
 
 	return errors
 }
 
+func (x XAICollector) collectUsageBreakdownMetrics(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	account xaiAccount,
+	errors []string,
+) []string {
+	now := time.Now().UTC()
+	periods := []struct {
+		name     string
+		timeUnit string
+		start    time.Time
+	}{
+		{"monthly", "TIME_UNIT_MONTH", time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)},
+		{"daily", "TIME_UNIT_DAY", time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, period := range periods {
+		groups, err := x.xaiCollectUsageGroups(ctx, account, period.start, now, period.timeUnit)
+		if err != nil {
+			log.Error(fmt.Sprintf("error collecting X.AI %s usage breakdown: %s", period.name, err))
+			errors = append(errors, period.name+" usage breakdown")
+			continue
+		}
+
+		for _, g := range capUsageGroups(groups, x.Cfg.XAIUsageMaxSeries) {
+			model, apiKey := g.labels[0], g.labels[1]
+
+			ch <- prometheus.MustNewConstMetric(
+				xaiUsageByModel,
+				prometheus.GaugeValue,
+				g.values[0],
+				[]string{account.Name, period.name, model, apiKey, "USD", successStatus}...,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				xaiRequests,
+				prometheus.GaugeValue,
+				g.values[1],
+				[]string{account.Name, period.name, model, apiKey, successStatus}...,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				xaiTokens,
+				prometheus.GaugeValue,
+				g.values[2],
+				[]string{account.Name, period.name, model, apiKey, "prompt", successStatus}...,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				xaiTokens,
+				prometheus.GaugeValue,
+				g.values[3],
+				[]string{account.Name, period.name, model, apiKey, "completion", successStatus}...,
+			)
+		}
+	}
+
+	return errors
+}
+
 func (x XAICollector) collectSpendingLimitMetrics(
 	ctx context.Context,
 	ch chan<- prometheus.Metric,
+	account xaiAccount,
 	errors []string,
 ) []string {
 	spendingStatus := successStatus
-	spendingLimits, err := x.xaiCollectSpendingLimits(ctx)
+	spendingLimits, err := x.xaiCollectSpendingLimits(ctx, account)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting X.AI spending limits: %s", err))
 		errors = append(errors, "spending limits")
@@ -219,28 +434,28 @@ func (x XAICollector) collectSpendingLimitMetrics(
 		xaiSpendingLimit,
 		prometheus.GaugeValue,
 		hardSlAuto,
-		[]string{"hard_sl_auto", "USD", spendingStatus}...,
+		[]string{account.Name, "hard_sl_auto", "USD", spendingStatus}...,
 	)
 
 	ch <- prometheus.MustNewConstMetric(
 		xaiSpendingLimit,
 		prometheus.GaugeValue,
 		effectiveHardSl,
-		[]string{"effective_hard_sl", "USD", spendingStatus}...,
+		[]string{account.Name, "effective_hard_sl", "USD", spendingStatus}...,
 	)
 
 	ch <- prometheus.MustNewConstMetric(
 		xaiSpendingLimit,
 		prometheus.GaugeValue,
 		softSl,
-		[]string{"soft_sl", "USD", spendingStatus}...,
+		[]string{account.Name, "soft_sl", "USD", spendingStatus}...,
 	)
 
 	ch <- prometheus.MustNewConstMetric(
 		xaiSpendingLimit,
 		prometheus.GaugeValue,
 		effectiveSl,
-		[]string{"effective_sl", "USD", spendingStatus}...,
+		[]string{account.Name, "effective_sl", "USD", spendingStatus}...,
 	)
 
 	return errors
@@ -249,10 +464,11 @@ func (x XAICollector) collectSpendingLimitMetrics(
 func (x XAICollector) collectBalanceMetrics(
 	ctx context.Context,
 	ch chan<- prometheus.Metric,
+	account xaiAccount,
 	errors []string,
 ) []string {
 	balanceStatus := successStatus
-	balance, err := x.xaiCollectBalance(ctx)
+	balance, err := x.xaiCollectBalance(ctx, account)
 	if err != nil {
 		log.Error(fmt.Sprintf("error collecting X.AI balance: %s", err))
 		errors = append(errors, "balance")
@@ -267,76 +483,162 @@ func (x XAICollector) collectBalanceMetrics(
 		xaiBalance,
 		prometheus.GaugeValue,
 		totalBalance,
-		[]string{"USD", balanceStatus}...,
+		[]string{account.Name, "USD", balanceStatus}...,
 	)
 
+	if balanceStatus == successStatus {
+		x.History.collect(ch, xaiBurnRate, xaiDepletion, account.Key, totalBalance, account.Name, "USD")
+	}
+
 	return errors
 }
 
-func (x XAICollector) xaiCollectUsageMonthly(ctx context.Context) (float64, error) {
+func (x XAICollector) xaiCollectUsageMonthly(ctx context.Context, account xaiAccount) (float64, error) {
 	now := time.Now().UTC()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
 
-	return x.xaiCollectUsage(ctx, startOfMonth, now, "TIME_UNIT_MONTH")
+	return x.xaiCollectUsage(ctx, account, startOfMonth, now, "TIME_UNIT_MONTH")
 }
 
-func (x XAICollector) xaiCollectUsageDaily(ctx context.Context) (float64, error) {
+func (x XAICollector) xaiCollectUsageDaily(ctx context.Context, account xaiAccount) (float64, error) {
 	now := time.Now().UTC()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
-	return x.xaiCollectUsage(ctx, startOfDay, now, "TIME_UNIT_DAY")
+	return x.xaiCollectUsage(ctx, account, startOfDay, now, "TIME_UNIT_DAY")
 }
 
 func (x XAICollector) xaiCollectUsage(
 	ctx context.Context,
+	account xaiAccount,
 	startTime, endTime time.Time,
 	timeUnit string,
 ) (float64, error) {
-	url := fmt.Sprintf("%s/billing/teams/%s/usage", xaiAPIURL, x.Cfg.XAITeamID)
+	xaiResponse, err := x.xaiQueryUsage(
+		ctx,
+		account,
+		startTime, endTime,
+		timeUnit,
+		[]string{"description"},
+		[]XAIUsageValue{{Name: "usd", Aggregation: xaiAggregationSum}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var totalCost float64
+	for _, series := range xaiResponse.TimeSeries {
+		for _, dataPoint := range series.DataPoints {
+			for _, value := range dataPoint.Values {
+				totalCost += value
+			}
+		}
+	}
+
+	return totalCost, nil
+}
+
+// xaiCollectUsageGroups returns usage grouped by xaiUsageGroupBy, with the
+// values of every group summed over the time range in xaiUsageValues order.
+func (x XAICollector) xaiCollectUsageGroups(
+	ctx context.Context,
+	account xaiAccount,
+	startTime, endTime time.Time,
+	timeUnit string,
+) ([]usageGroup, error) {
+	xaiResponse, err := x.xaiQueryUsage(
+		ctx,
+		account,
+		startTime, endTime,
+		timeUnit,
+		xaiUsageGroupBy,
+		xaiUsageValues,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []usageGroup{}
+	index := map[string]int{}
+	for _, series := range xaiResponse.TimeSeries {
+		labels := make([]string, len(xaiUsageGroupBy))
+		for i := range labels {
+			labels[i] = xaiGroupLabel(series.Group, series.GroupLabels, i)
+		}
+
+		key := strings.Join(labels, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, usageGroup{
+				labels: labels,
+				values: make([]float64, len(xaiUsageValues)),
+			})
+		}
+
+		for _, dataPoint := range series.DataPoints {
+			for j, value := range dataPoint.Values {
+				if j < len(groups[i].values) {
+					groups[i].values[j] += value
+				}
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+func (x XAICollector) xaiQueryUsage(
+	ctx context.Context,
+	account xaiAccount,
+	startTime, endTime time.Time,
+	timeUnit string,
+	groupBy []string,
+	values []XAIUsageValue,
+) (XAIUsageResponse, error) {
+	url := fmt.Sprintf("%s/billing/teams/%s/usage", x.Cfg.XAIAPIURL, account.teamID)
 
 	var requestBody XAIUsageRequest
 	requestBody.AnalyticsRequest.TimeRange.StartTime = startTime.Format("2006-01-02 15:04:05")
 	requestBody.AnalyticsRequest.TimeRange.EndTime = endTime.Format("2006-01-02 15:04:05")
 	requestBody.AnalyticsRequest.TimeRange.Timezone = "Etc/GMT"
 	requestBody.AnalyticsRequest.TimeUnit = timeUnit
-	requestBody.AnalyticsRequest.Values = []struct {
-		Name        string `json:"name"`
-		Aggregation string `json:"aggregation"`
-	}{
-		{
-			Name:        "usd",
-			Aggregation: "AGGREGATION_SUM",
-		},
-	}
-	requestBody.AnalyticsRequest.GroupBy = []string{"description"}
+	requestBody.AnalyticsRequest.Values = values
+	requestBody.AnalyticsRequest.GroupBy = groupBy
 	requestBody.AnalyticsRequest.Filters = []interface{}{}
 
-	data, err := http.PostRequest(ctx, url, x.Cfg.XAIAPIKey, requestBody, x.Cfg.HTTPTimeout)
+	data, err := http.PostRequest(ctx, url, account.Key, requestBody, x.Cfg.HTTPTimeout)
 	if err != nil {
-		return 0, err
+		return XAIUsageResponse{}, err
 	}
 
 	var xaiResponse XAIUsageResponse
 	if err = json.NewDecoder(bytes.NewReader(data)).Decode(&xaiResponse); err != nil {
-		return 0, fmt.Errorf("error decoding response: %w", err)
+		return XAIUsageResponse{}, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var totalCost float64
-	for _, series := range xaiResponse.TimeSeries {
-		for _, dataPoint := range series.DataPoints {
-			for _, value := range dataPoint.Values {
-				totalCost += value
-			}
-		}
-	}
+	return xaiResponse, nil
+}
 
-	return totalCost, nil
+// xaiGroupLabel prefers the human readable group label, e.g. a model or API
+// key name, over the raw group value.
+func xaiGroupLabel(group, groupLabels []string, i int) string {
+	if i < len(groupLabels) && groupLabels[i] != "" {
+		return groupLabels[i]
+	}
+	if i < len(group) && group[i] != "" {
+		return group[i]
+	}
+	return unknownGroup
 }
 
-func (x XAICollector) xaiCollectSpendingLimits(ctx context.Context) (XAISpendingLimitsResponse, error) {
-	url := fmt.Sprintf("%s/billing/teams/%s/postpaid/spending-limits", xaiAPIURL, x.Cfg.XAITeamID)
+func (x XAICollector) xaiCollectSpendingLimits(
+	ctx context.Context,
+	account xaiAccount,
+) (XAISpendingLimitsResponse, error) {
+	url := fmt.Sprintf("%s/billing/teams/%s/postpaid/spending-limits", x.Cfg.XAIAPIURL, account.teamID)
 
-	data, err := http.GetRequest(ctx, url, x.Cfg.XAIAPIKey, x.Cfg.HTTPTimeout)
+	data, err := http.GetRequest(ctx, url, account.Key, x.Cfg.HTTPTimeout)
 	if err != nil {
 		return XAISpendingLimitsResponse{}, err
 	}
@@ -349,10 +651,10 @@ func (x XAICollector) xaiCollectSpendingLimits(ctx context.Context) (XAISpending
 	return xaiResponse, nil
 }
 
-func (x XAICollector) xaiCollectBalance(ctx context.Context) (XAIBalanceResponse, error) {
-	url := fmt.Sprintf("%s/billing/teams/%s/prepaid/balance", xaiAPIURL, x.Cfg.XAITeamID)
+func (x XAICollector) xaiCollectBalance(ctx context.Context, account xaiAccount) (XAIBalanceResponse, error) {
+	url := fmt.Sprintf("%s/billing/teams/%s/prepaid/balance", x.Cfg.XAIAPIURL, account.teamID)
 
-	data, err := http.GetRequest(ctx, url, x.Cfg.XAIAPIKey, x.Cfg.HTTPTimeout)
+	data, err := http.GetRequest(ctx, url, account.Key, x.Cfg.HTTPTimeout)
 	if err != nil {
 		return XAIBalanceResponse{}, err
 	}
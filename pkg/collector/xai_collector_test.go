@@ -0,0 +1,143 @@
+package collector
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+)
+
+const testXAIKey = "xai-management-test"
+
+// newXAIServer serves the X.AI usage breakdown fixture from testdata and
+// fails the test on usage requests that are not authenticated or not shaped
+// like the collector's breakdown query. Other endpoints are not found.
+func newXAIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/billing/teams/team-1/usage" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+testXAIKey {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+
+		var req XAIUsageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("error decoding request: %s", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The total usage is grouped by description and has no fixture
+		if !slices.Equal(req.AnalyticsRequest.GroupBy, xaiUsageGroupBy) {
+			_, _ = w.Write([]byte(`{"timeSeries": []}`))
+			return
+		}
+
+		if !slices.Equal(req.AnalyticsRequest.Values, xaiUsageValues) {
+			t.Errorf("values = %v, want %v", req.AnalyticsRequest.Values, xaiUsageValues)
+		}
+		if unit := req.AnalyticsRequest.TimeUnit; unit != "TIME_UNIT_MONTH" && unit != "TIME_UNIT_DAY" {
+			t.Errorf("unexpected time unit %q", unit)
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", "xai_usage_breakdown.json"))
+		if err != nil {
+			t.Errorf("error reading fixture: %s", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+func newTestXAICollector(serverURL string, maxSeries int) XAICollector {
+	return XAICollector{
+		Cfg: config.Config{
+			XAIAPIKey:         "team:" + testXAIKey,
+			XAITeamID:         "team-1",
+			XAIAPIURL:         serverURL,
+			XAIUsageMaxSeries: maxSeries,
+			HTTPTimeout:       5,
+			Timeout:           5,
+		},
+	}
+}
+
+// TestXAICollectUsageGroups tests that usage is summed per group, labelled
+// with the group labels when present and the raw group values otherwise.
+func TestXAICollectUsageGroups(t *testing.T) {
+	server := newXAIServer(t)
+	defer server.Close()
+
+	x := newTestXAICollector(server.URL, 0)
+	groups, err := x.xaiCollectUsageGroups(t.Context(), x.accounts()[0], time.Now(), time.Now(), "TIME_UNIT_DAY")
+	if err != nil {
+		t.Fatalf("xaiCollectUsageGroups() error = %s", err)
+	}
+
+	expected := map[[2]string][]float64{
+		{"grok-4", "ci"}:             {4, 15, 1500, 300},
+		{"grok-3-mini", "key-id-2"}:  {0.5, 3, 300, 30},
+		{unknownGroup, unknownGroup}: {0.25, 1, 100, 10},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(expected))
+	}
+	for _, g := range groups {
+		want := expected[[2]string{g.labels[0], g.labels[1]}]
+		if !slices.Equal(g.values, want) {
+			t.Errorf("group %v = %v, want %v", g.labels, g.values, want)
+		}
+	}
+}
+
+// TestXAICollectUsageBreakdown tests that groups beyond XAI_USAGE_MAX_SERIES
+// are folded into "other" for both periods.
+func TestXAICollectUsageBreakdown(t *testing.T) {
+	server := newXAIServer(t)
+	defer server.Close()
+
+	metrics := collectGauges(t, newTestXAICollector(server.URL, 2))
+
+	usage := map[[3]string]float64{}
+	for _, m := range metrics[xaiUsageByModel] {
+		labels := metricLabels(m)
+		if labels["account"] != "team" || labels["status"] != successStatus {
+			t.Errorf("unexpected usage labels %v", labels)
+		}
+		usage[[3]string{labels["period"], labels["model"], labels["api_key"]}] = m.GetGauge().GetValue()
+	}
+
+	for _, period := range []string{"monthly", "daily"} {
+		expected := map[[3]string]float64{
+			{period, "grok-4", "ci"}:            4,
+			{period, "grok-3-mini", "key-id-2"}: 0.5,
+			{period, otherGroup, otherGroup}:    0.25,
+		}
+		for group, value := range expected {
+			if usage[group] != value {
+				t.Errorf("usage%v = %v, want %v", group, usage[group], value)
+			}
+		}
+	}
+
+	if len(usage) != 6 {
+		t.Errorf("got %d usage series, want 6", len(usage))
+	}
+
+	// 2 periods * 3 groups * 2 token types.
+	if len(metrics[xaiTokens]) != 12 {
+		t.Errorf("got %d token series, want 12", len(metrics[xaiTokens]))
+	}
+}
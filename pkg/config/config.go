@@ -4,8 +4,9 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
-	"math"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,50 +20,103 @@ import (
 
 var errConfig = errors.New("config error")
 
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// maxCallRecvMsgSize bounds gRPC responses, it leaves room for full blocks
+// while keys and requests are paged.
+const maxCallRecvMsgSize = 64 << 20
+
+// defaultCoinGeckoSymbolMap is used when COINGECKO_SYMBOL_MAP is empty.
+const defaultCoinGeckoSymbolMap = "WARD=warden-protocol,ETH=ethereum,BNB=binancecoin,SOL=solana,BTC=bitcoin"
+
 func configError(msg string) error {
 	return fmt.Errorf("%w: %s", errConfig, msg)
 }
 
 //nolint:lll //this struct cannot be changed to smaller one
 type Config struct {
-	GRPCAddr           string `env:"GRPC_ADDR"            envDefault:"grpc.wardenprotocol.org:443" mapstructure:"GRPC_ADDR"`
-	EnvFile            string `env:"ENV_FILE"             envDefault:""`
-	Port               string `env:"PORT"                 envDefault:"8081"                        mapstructure:"PORT"`
-	TLS                bool   `env:"GRPC_TLS_ENABLED"     envDefault:"true"                        mapstructure:"GRPC_TLS_ENABLED"`
-	Timeout            int    `env:"GRPC_TIMEOUT_SECONDS" envDefault:"45"                          mapstructure:"GRPC_TIMEOUT_SECONDS"`
-	TTL                int    `env:"TTL"                  envDefault:"60"                          mapstructure:"TTL"`
-	ChainID            string `env:"CHAIN_ID"             envDefault:"warden_8765-1"               mapstructure:"CHAIN_ID"`
-	ValidatorMetrics   bool   `env:"VALIDATOR_METRICS"    envDefault:"true"                        mapstructure:"VALIDATOR_METRICS"`
-	MintMetrics        bool   `env:"MINT_METRICS"         envDefault:"true"                        mapstructure:"MINT_METRICS"`
-	WalletAddresses    string `env:"WALLET_ADDRESSES"     envDefault:""                            mapstructure:"WALLET_ADDRESSES"`
-	Denom              string `env:"DENOM"                envDefault:"award"                       mapstructure:"DENOM"`
-	Exponent           int    `env:"EXPONENT"             envDefault:"18"                          mapstructure:"EXPONENT"`
-	VeniceMetrics      bool   `env:"VENICE_METRICS"        envDefault:"false"                       mapstructure:"VENICE_METRICS"`
-	VeniceAPIKey       string `env:"VENICE_API_KEY"        envDefault:""                            mapstructure:"VENICE_API_KEY"`
-	VeniceUsageMetrics bool   `env:"VENICE_USAGE_METRICS"  envDefault:"true"                        mapstructure:"VENICE_USAGE_METRICS"`
-	MessariMetrics     bool   `env:"MESSARI_METRICS"      envDefault:"false"                       mapstructure:"MESSARI_METRICS"`
-	MessariAPIKey      string `env:"MESSARI_API_KEY"      envDefault:""                            mapstructure:"MESSARI_API_KEY"`
-	BaseMetrics        bool   `env:"BASE_METRICS"         envDefault:"false"                       mapstructure:"BASE_METRICS"`
-	BaseRPCURL         string `env:"BASE_RPC_URL"         envDefault:""                            mapstructure:"BASE_RPC_URL"`
-	BaseAddresses      string `env:"BASE_ADDRESSES"       envDefault:""                            mapstructure:"BASE_ADDRESSES"`
-	BnbMetrics         bool   `env:"BNB_METRICS"          envDefault:"false"                       mapstructure:"BNB_METRICS"`
-	BnbRPCURL          string `env:"BNB_RPC_URL"          envDefault:""                            mapstructure:"BNB_RPC_URL"`
-	BnbAddresses       string `env:"BNB_ADDRESSES"        envDefault:""                            mapstructure:"BNB_ADDRESSES"`
-	CoinGeckoMetrics   bool   `env:"COINGECKO_METRICS"    envDefault:"false"                       mapstructure:"COINGECKO_METRICS"`
-	CoinGeckoAPIKey    string `env:"COINGECKO_API_KEY"    envDefault:""                            mapstructure:"COINGECKO_API_KEY"`
-	XAIMetrics         bool   `env:"XAI_METRICS"          envDefault:"false"                       mapstructure:"XAI_METRICS"`
-	XAIAPIKey          string `env:"XAI_API_KEY"          envDefault:""                            mapstructure:"XAI_API_KEY"`
-	XAITeamID          string `env:"XAI_TEAM_ID"          envDefault:""                            mapstructure:"XAI_TEAM_ID"`
-	OpenAIMetrics      bool   `env:"OPENAI_METRICS"       envDefault:"false"                       mapstructure:"OPENAI_METRICS"`
-	OpenAIAPIKey       string `env:"OPENAI_API_KEY"       envDefault:""                            mapstructure:"OPENAI_API_KEY"`
-	TavilyMetrics      bool   `env:"TAVILY_METRICS"       envDefault:"false"                       mapstructure:"TAVILY_METRICS"`
-	TavilyAPIKey       string `env:"TAVILY_API_KEY"       envDefault:""                            mapstructure:"TAVILY_API_KEY"`
-	OpenRouterMetrics  bool   `env:"OPENROUTER_METRICS"   envDefault:"false"                       mapstructure:"OPENROUTER_METRICS"`
-	OpenRouterAPIKey   string `env:"OPENROUTER_API_KEY"   envDefault:""                            mapstructure:"OPENROUTER_API_KEY"`
-	ComposioMetrics    bool   `env:"COMPOSIO_METRICS"     envDefault:"false"                       mapstructure:"COMPOSIO_METRICS"`
-	ComposioAPIKey     string `env:"COMPOSIO_API_KEY"     envDefault:""                            mapstructure:"COMPOSIO_API_KEY"`
-	HTTPTimeout        int    `env:"HTTP_TIMEOUT_SECONDS" envDefault:"10"                          mapstructure:"HTTP_TIMEOUT_SECONDS"`
-	BlockWindow        int64  `env:"BLOCK_WINDOW"         envDefault:"200"                         mapstructure:"BLOCK_WINDOW"`
+	GRPCAddr                    string `env:"GRPC_ADDR"                      envDefault:"grpc.wardenprotocol.org:443"          mapstructure:"GRPC_ADDR"`
+	EnvFile                     string `env:"ENV_FILE"                       envDefault:""`
+	Port                        string `env:"PORT"                           envDefault:"8081"                                 mapstructure:"PORT"`
+	TLS                         bool   `env:"GRPC_TLS_ENABLED"               envDefault:"true"                                 mapstructure:"GRPC_TLS_ENABLED"`
+	Timeout                     int    `env:"GRPC_TIMEOUT_SECONDS"           envDefault:"45"                                   mapstructure:"GRPC_TIMEOUT_SECONDS"`
+	TTL                         int    `env:"TTL"                            envDefault:"60"                                   mapstructure:"TTL"`
+	ChainID                     string `env:"CHAIN_ID"                       envDefault:"warden_8765-1"                        mapstructure:"CHAIN_ID"`
+	ValidatorMetrics            bool   `env:"VALIDATOR_METRICS"              envDefault:"true"                                 mapstructure:"VALIDATOR_METRICS"`
+	MintMetrics                 bool   `env:"MINT_METRICS"                   envDefault:"true"                                 mapstructure:"MINT_METRICS"`
+	WardenMetrics               bool   `env:"WARDEN_METRICS"                 envDefault:"false"                                mapstructure:"WARDEN_METRICS"`
+	WardenSpaceMetrics          bool   `env:"WARDEN_SPACE_METRICS"           envDefault:"false"                                mapstructure:"WARDEN_SPACE_METRICS"`
+	WardenSpaceIDs              string `env:"WARDEN_SPACE_IDS"               envDefault:""                                     mapstructure:"WARDEN_SPACE_IDS"`
+	WardenKeyPageSize           int    `env:"WARDEN_KEY_PAGE_SIZE"           envDefault:"1000"                                 mapstructure:"WARDEN_KEY_PAGE_SIZE"`
+	WardenKeyCountInterval      int    `env:"WARDEN_KEY_COUNT_INTERVAL"      envDefault:"60"                                   mapstructure:"WARDEN_KEY_COUNT_INTERVAL"`
+	WardenKeyCheckpointFile     string `env:"WARDEN_KEY_CHECKPOINT_FILE"     envDefault:""                                     mapstructure:"WARDEN_KEY_CHECKPOINT_FILE"`
+	WalletAddresses             string `env:"WALLET_ADDRESSES"               envDefault:""                                     mapstructure:"WALLET_ADDRESSES"`
+	WalletAccountMetrics        bool   `env:"WALLET_ACCOUNT_METRICS"         envDefault:"false"                                mapstructure:"WALLET_ACCOUNT_METRICS"`
+	WalletVestingMetrics        bool   `env:"WALLET_VESTING_METRICS"         envDefault:"false"                                mapstructure:"WALLET_VESTING_METRICS"`
+	Denom                       string `env:"DENOM"                          envDefault:"award"                                mapstructure:"DENOM"`
+	Exponent                    int    `env:"EXPONENT"                       envDefault:"18"                                   mapstructure:"EXPONENT"`
+	Symbol                      string `env:"SYMBOL"                         envDefault:"WARD"                                 mapstructure:"SYMBOL"`
+	VeniceMetrics               bool   `env:"VENICE_METRICS"                 envDefault:"false"                                mapstructure:"VENICE_METRICS"`
+	VeniceAPIKey                string `env:"VENICE_API_KEY"                 envDefault:""                                     mapstructure:"VENICE_API_KEY"`
+	VeniceUsageMetrics          bool   `env:"VENICE_USAGE_METRICS"           envDefault:"true"                                 mapstructure:"VENICE_USAGE_METRICS"`
+	MessariMetrics              bool   `env:"MESSARI_METRICS"                envDefault:"false"                                mapstructure:"MESSARI_METRICS"`
+	MessariAPIKey               string `env:"MESSARI_API_KEY"                envDefault:""                                     mapstructure:"MESSARI_API_KEY"`
+	BaseMetrics                 bool   `env:"BASE_METRICS"                   envDefault:"false"                                mapstructure:"BASE_METRICS"`
+	BaseRPCURL                  string `env:"BASE_RPC_URL"                   envDefault:""                                     mapstructure:"BASE_RPC_URL"`
+	BaseAddresses               string `env:"BASE_ADDRESSES"                 envDefault:""                                     mapstructure:"BASE_ADDRESSES"`
+	BnbMetrics                  bool   `env:"BNB_METRICS"                    envDefault:"false"                                mapstructure:"BNB_METRICS"`
+	BnbRPCURL                   string `env:"BNB_RPC_URL"                    envDefault:""                                     mapstructure:"BNB_RPC_URL"`
+	BnbAddresses                string `env:"BNB_ADDRESSES"                  envDefault:""                                     mapstructure:"BNB_ADDRESSES"`
+	CoinGeckoMetrics            bool   `env:"COINGECKO_METRICS"              envDefault:"false"                                mapstructure:"COINGECKO_METRICS"`
+	CoinGeckoAPIKey             string `env:"COINGECKO_API_KEY"              envDefault:""                                     mapstructure:"COINGECKO_API_KEY"`
+	CoinGeckoSymbolMap          string `env:"COINGECKO_SYMBOL_MAP"           envDefault:""                                     mapstructure:"COINGECKO_SYMBOL_MAP"`
+	CoinGeckoAPIURL             string `env:"COINGECKO_API_URL"              envDefault:"https://pro-api.coingecko.com/api/v3" mapstructure:"COINGECKO_API_URL"`
+	BalanceUSDMetrics           bool   `env:"BALANCE_USD_METRICS"            envDefault:"false"                                mapstructure:"BALANCE_USD_METRICS"`
+	PortfolioMetrics            bool   `env:"PORTFOLIO_METRICS"              envDefault:"false"                                mapstructure:"PORTFOLIO_METRICS"`
+	WalletGroups                string `env:"WALLET_GROUPS"                  envDefault:""                                     mapstructure:"WALLET_GROUPS"`
+	XAIMetrics                  bool   `env:"XAI_METRICS"                    envDefault:"false"                                mapstructure:"XAI_METRICS"`
+	XAIAPIKey                   string `env:"XAI_API_KEY"                    envDefault:""                                     mapstructure:"XAI_API_KEY"`
+	XAITeamID                   string `env:"XAI_TEAM_ID"                    envDefault:""                                     mapstructure:"XAI_TEAM_ID"`
+	XAIUsageMaxSeries           int    `env:"XAI_USAGE_MAX_SERIES"           envDefault:"50"                                   mapstructure:"XAI_USAGE_MAX_SERIES"`
+	XAIAPIURL                   string `env:"XAI_API_URL"                    envDefault:"https://management-api.x.ai/v1"       mapstructure:"XAI_API_URL"`
+	OpenAIMetrics               bool   `env:"OPENAI_METRICS"                 envDefault:"false"                                mapstructure:"OPENAI_METRICS"`
+	OpenAIAPIKey                string `env:"OPENAI_API_KEY"                 envDefault:""                                     mapstructure:"OPENAI_API_KEY"`
+	OpenAIAPIURL                string `env:"OPENAI_API_URL"                 envDefault:"https://api.openai.com/v1"            mapstructure:"OPENAI_API_URL"`
+	TavilyMetrics               bool   `env:"TAVILY_METRICS"                 envDefault:"false"                                mapstructure:"TAVILY_METRICS"`
+	TavilyAPIKey                string `env:"TAVILY_API_KEY"                 envDefault:""                                     mapstructure:"TAVILY_API_KEY"`
+	OpenRouterMetrics           bool   `env:"OPENROUTER_METRICS"             envDefault:"false"                                mapstructure:"OPENROUTER_METRICS"`
+	OpenRouterAPIKey            string `env:"OPENROUTER_API_KEY"             envDefault:""                                     mapstructure:"OPENROUTER_API_KEY"`
+	OpenRouterProvisioningKey   string `env:"OPENROUTER_PROVISIONING_KEY"    envDefault:""                                     mapstructure:"OPENROUTER_PROVISIONING_KEY"`
+	OpenRouterActivityMaxSeries int    `env:"OPENROUTER_ACTIVITY_MAX_SERIES" envDefault:"50"                                   mapstructure:"OPENROUTER_ACTIVITY_MAX_SERIES"`
+	OpenRouterAPIURL            string `env:"OPENROUTER_API_URL"             envDefault:"https://openrouter.ai/api/v1"         mapstructure:"OPENROUTER_API_URL"`
+	AnthropicMetrics            bool   `env:"ANTHROPIC_METRICS"              envDefault:"false"                                mapstructure:"ANTHROPIC_METRICS"`
+	AnthropicAdminKey           string `env:"ANTHROPIC_ADMIN_KEY"            envDefault:""                                     mapstructure:"ANTHROPIC_ADMIN_KEY"`
+	AnthropicAPIURL             string `env:"ANTHROPIC_API_URL"              envDefault:"https://api.anthropic.com/v1"         mapstructure:"ANTHROPIC_API_URL"`
+	GeminiMetrics               bool   `env:"GEMINI_METRICS"                 envDefault:"false"                                mapstructure:"GEMINI_METRICS"`
+	GeminiBillingURL            string `env:"GEMINI_BILLING_URL"             envDefault:""                                     mapstructure:"GEMINI_BILLING_URL"`
+	GeminiBillingToken          string `env:"GEMINI_BILLING_TOKEN"           envDefault:""                                     mapstructure:"GEMINI_BILLING_TOKEN"`
+	ComposioMetrics             bool   `env:"COMPOSIO_METRICS"               envDefault:"false"                                mapstructure:"COMPOSIO_METRICS"`
+	ComposioAPIKey              string `env:"COMPOSIO_API_KEY"               envDefault:""                                     mapstructure:"COMPOSIO_API_KEY"`
+	HTTPTimeout                 int    `env:"HTTP_TIMEOUT_SECONDS"           envDefault:"10"                                   mapstructure:"HTTP_TIMEOUT_SECONDS"`
+	BlockWindow                 int64  `env:"BLOCK_WINDOW"                   envDefault:"200"                                  mapstructure:"BLOCK_WINDOW"`
+	TxMetrics                   bool   `env:"TX_METRICS"                     envDefault:"false"                                mapstructure:"TX_METRICS"`
+	CometBFTMetrics             bool   `env:"COMETBFT_METRICS"               envDefault:"false"                                mapstructure:"COMETBFT_METRICS"`
+	CometBFTRPCURL              string `env:"COMETBFT_RPC_URL"               envDefault:"http://localhost:26657"               mapstructure:"COMETBFT_RPC_URL"`
+	NodeMetrics                 bool   `env:"NODE_METRICS"                   envDefault:"false"                                mapstructure:"NODE_METRICS"`
+	NodeEndpoints               string `env:"NODE_ENDPOINTS"                 envDefault:""                                     mapstructure:"NODE_ENDPOINTS"`
+	IBCMetrics                  bool   `env:"IBC_METRICS"                    envDefault:"false"                                mapstructure:"IBC_METRICS"`
+	IBCChannels                 string `env:"IBC_CHANNELS"                   envDefault:""                                     mapstructure:"IBC_CHANNELS"`
+	IBCCounterpartyEndpoints    string `env:"IBC_COUNTERPARTY_ENDPOINTS"     envDefault:""                                     mapstructure:"IBC_COUNTERPARTY_ENDPOINTS"`
+	OracleMetrics               bool   `env:"ORACLE_METRICS"                 envDefault:"false"                                mapstructure:"ORACLE_METRICS"`
+	OracleBlockWindow           int64  `env:"ORACLE_BLOCK_WINDOW"            envDefault:"50"                                   mapstructure:"ORACLE_BLOCK_WINDOW"`
+	EVMMetrics                  bool   `env:"EVM_METRICS"                    envDefault:"false"                                mapstructure:"EVM_METRICS"`
+	EVMRPCURL                   string `env:"EVM_RPC_URL"                    envDefault:"http://localhost:8545"                mapstructure:"EVM_RPC_URL"`
+	BurnRateMetrics             bool   `env:"BURN_RATE_METRICS"              envDefault:"false"                                mapstructure:"BURN_RATE_METRICS"`
+	BurnRateWindows             string `env:"BURN_RATE_WINDOWS"              envDefault:"1h,6h,24h"                            mapstructure:"BURN_RATE_WINDOWS"`
+	ConstLabels                 string `env:"CONST_LABELS"                   envDefault:""                                     mapstructure:"CONST_LABELS"`
+	TargetLabels                string `env:"TARGET_LABELS"                  envDefault:""                                     mapstructure:"TARGET_LABELS"`
+	UpMetrics                   bool   `env:"UP_METRICS"                     envDefault:"false"                                mapstructure:"UP_METRICS"`
 }
 
 func LoadConfig() (Config, error) {
@@ -80,6 +134,14 @@ func LoadConfig() (Config, error) {
 			return Config{}, configError(err.Error())
 		}
 	}
+
+	if err = cfg.validateAPIKeys(); err != nil {
+		return Config{}, err
+	}
+
+	if err = cfg.validateGemini(); err != nil {
+		return Config{}, err
+	}
 	return cfg, nil
 }
 
@@ -113,6 +175,403 @@ func loadConfigFile(cfg *Config) error {
 	return nil
 }
 
+// APIAccount is a configured API key together with the value of the account
+// label its series are exported with.
+type APIAccount struct {
+	Name    string
+	Key     string
+	Aliased bool
+}
+
+// ParseAPIAccounts parses a comma-separated list of API keys. Each entry may
+// be prefixed with an alias as "alias:key"; entries without an alias are
+// labelled with the redacted key.
+func ParseAPIAccounts(s string) []APIAccount {
+	accounts := []APIAccount{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		alias, key, found := strings.Cut(entry, ":")
+		alias = strings.TrimSpace(alias)
+		key = strings.TrimSpace(key)
+		if !found || alias == "" || key == "" {
+			accounts = append(accounts, APIAccount{Name: redactKey(entry), Key: entry})
+			continue
+		}
+		accounts = append(accounts, APIAccount{Name: alias, Key: key, Aliased: true})
+	}
+	return accounts
+}
+
+func redactKey(k string) string {
+	if len(k) < 8 {
+		return "***"
+	}
+	prefix := k
+	if len(prefix) > 12 {
+		prefix = k[:12]
+	}
+	suffix := k
+	if len(suffix) > 3 {
+		suffix = k[len(k)-3:]
+	}
+	return prefix + "..." + suffix
+}
+
+// validateAPIKeys rejects API key lists with an alias or key used twice, as
+// both entries would be exported with the same account label.
+func (c Config) validateAPIKeys() error {
+	for _, setting := range []struct {
+		name  string
+		value string
+	}{
+		{"VENICE_API_KEY", c.VeniceAPIKey},
+		{"MESSARI_API_KEY", c.MessariAPIKey},
+		{"COINGECKO_API_KEY", c.CoinGeckoAPIKey},
+		{"XAI_API_KEY", c.XAIAPIKey},
+		{"OPENAI_API_KEY", c.OpenAIAPIKey},
+		{"TAVILY_API_KEY", c.TavilyAPIKey},
+		{"OPENROUTER_API_KEY", c.OpenRouterAPIKey},
+		{"OPENROUTER_PROVISIONING_KEY", c.OpenRouterProvisioningKey},
+		{"ANTHROPIC_ADMIN_KEY", c.AnthropicAdminKey},
+	} {
+		aliases := map[string]bool{}
+		keys := map[string]bool{}
+
+		for _, account := range ParseAPIAccounts(setting.value) {
+			if account.Aliased {
+				if aliases[account.Name] {
+					return configError(fmt.Sprintf("duplicate alias %q in %s", account.Name, setting.name))
+				}
+				aliases[account.Name] = true
+			}
+
+			if keys[account.Key] {
+				return configError(fmt.Sprintf("duplicate key in %s", setting.name))
+			}
+			keys[account.Key] = true
+		}
+	}
+
+	return nil
+}
+
+// validateGemini rejects GEMINI_METRICS without a GEMINI_BILLING_URL, as
+// every scrape would fail.
+func (c Config) validateGemini() error {
+	if c.GeminiMetrics && strings.TrimSpace(c.GeminiBillingURL) == "" {
+		return configError("GEMINI_BILLING_URL is required with GEMINI_METRICS")
+	}
+
+	return nil
+}
+
+// BurnRateWindowDurations parses the comma-separated BURN_RATE_WINDOWS list.
+func (c Config) BurnRateWindowDurations() ([]time.Duration, error) {
+	windows := []time.Duration{}
+
+	for _, w := range strings.Split(c.BurnRateWindows, ",") {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(w)
+		if err != nil {
+			return nil, configError(fmt.Sprintf("invalid burn rate window %q: %s", w, err))
+		}
+
+		if d <= 0 {
+			return nil, configError(fmt.Sprintf("burn rate window %q must be positive", w))
+		}
+
+		windows = append(windows, d)
+	}
+
+	if len(windows) == 0 {
+		return nil, configError("at least one burn rate window is required")
+	}
+
+	return windows, nil
+}
+
+// CoinGeckoSymbols parses the comma-separated SYMBOL=coin-id pairs of
+// COINGECKO_SYMBOL_MAP, keyed by upper-case symbol. Malformed entries are
+// skipped.
+func (c Config) CoinGeckoSymbols() map[string]string {
+	symbolMap := c.CoinGeckoSymbolMap
+	if strings.TrimSpace(symbolMap) == "" {
+		symbolMap = defaultCoinGeckoSymbolMap
+	}
+
+	symbols := map[string]string{}
+
+	for _, pair := range strings.Split(symbolMap, ",") {
+		symbol, coinID, ok := strings.Cut(pair, "=")
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		coinID = strings.TrimSpace(coinID)
+		if !ok || symbol == "" || coinID == "" {
+			continue
+		}
+
+		symbols[symbol] = coinID
+	}
+
+	return symbols
+}
+
+// Chains a wallet in WALLET_GROUPS can live on.
+const (
+	CosmosChain = "cosmos"
+	BaseChain   = "base"
+	BnbChain    = "bnb"
+)
+
+type GroupWallet struct {
+	Chain   string
+	Address string
+}
+
+type WalletGroup struct {
+	Name    string
+	Wallets []GroupWallet
+}
+
+// ParseWalletGroups parses WALLET_GROUPS, a semicolon-separated list of
+// name=wallets groups where wallets is a comma-separated list of
+// chain:address entries, e.g.
+// "treasury=cosmos:warden1...,base:0xabc;relayers=bnb:0xdef". Wallets without
+// a chain prefix are Cosmos wallets.
+func (c Config) ParseWalletGroups() ([]WalletGroup, error) {
+	groups := []WalletGroup{}
+
+	for _, entry := range strings.Split(c.WalletGroups, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, wallets, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, configError(fmt.Sprintf("invalid wallet group %q", entry))
+		}
+
+		group := WalletGroup{Name: name}
+		for _, wallet := range strings.Split(wallets, ",") {
+			wallet = strings.TrimSpace(wallet)
+			if wallet == "" {
+				continue
+			}
+
+			chain, address, found := strings.Cut(wallet, ":")
+			if !found {
+				chain, address = CosmosChain, wallet
+			}
+			chain = strings.ToLower(strings.TrimSpace(chain))
+			address = strings.TrimSpace(address)
+
+			switch chain {
+			case CosmosChain, BaseChain, BnbChain:
+			default:
+				return nil, configError(fmt.Sprintf("unknown chain %q in wallet group %q", chain, name))
+			}
+
+			if address == "" {
+				return nil, configError(fmt.Sprintf("empty address in wallet group %q", name))
+			}
+
+			group.Wallets = append(group.Wallets, GroupWallet{Chain: chain, Address: address})
+		}
+
+		if len(group.Wallets) == 0 {
+			return nil, configError(fmt.Sprintf("wallet group %q has no wallets", name))
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// ParseConstLabels parses CONST_LABELS, a comma-separated list of name=value
+// pairs added to every series.
+func (c Config) ParseConstLabels() (map[string]string, error) {
+	return parseLabels(c.ConstLabels)
+}
+
+// ParseTargetLabels parses TARGET_LABELS, a semicolon-separated list of
+// target:labels entries where target is a wallet address, an API key alias
+// or a validator and labels is a comma-separated list of name=value pairs,
+// e.g. "warden1abc...:alias=faucet,team=infra;prod:environment=production".
+// Target labels may not reuse a name from CONST_LABELS.
+func (c Config) ParseTargetLabels() (map[string]map[string]string, error) {
+	constLabels, err := c.ParseConstLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := map[string]map[string]string{}
+
+	for _, entry := range strings.Split(c.TargetLabels, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		target, pairs, ok := strings.Cut(entry, ":")
+		target = strings.TrimSpace(target)
+		if !ok || target == "" {
+			return nil, configError(fmt.Sprintf("invalid target labels %q", entry))
+		}
+
+		labels, labelsErr := parseLabels(pairs)
+		if labelsErr != nil {
+			return nil, labelsErr
+		}
+
+		if targets[target] == nil {
+			targets[target] = map[string]string{}
+		}
+		for name, value := range labels {
+			if _, ok = constLabels[name]; ok {
+				return nil, configError(fmt.Sprintf("target label %q is already a const label", name))
+			}
+			targets[target][name] = value
+		}
+	}
+
+	return targets, nil
+}
+
+func parseLabels(s string) (map[string]string, error) {
+	labels := map[string]string{}
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !labelNameRegexp.MatchString(name) || strings.HasPrefix(name, "__") {
+			return nil, configError(fmt.Sprintf("invalid label %q", pair))
+		}
+
+		labels[name] = strings.TrimSpace(value)
+	}
+
+	return labels, nil
+}
+
+type NodeEndpoint struct {
+	Name string
+	Addr string
+	TLS  bool
+}
+
+// ParseNodeEndpoints parses NODE_ENDPOINTS, a comma-separated list of
+// name=address gRPC endpoints. Addresses may be prefixed with grpc:// for
+// plaintext or grpcs:// for TLS, otherwise GRPC_TLS_ENABLED applies.
+func (c Config) ParseNodeEndpoints() ([]NodeEndpoint, error) {
+	endpoints, err := c.parseEndpoints(c.NodeEndpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) == 0 {
+		return nil, configError("at least one node endpoint is required")
+	}
+
+	return endpoints, nil
+}
+
+// ParseIBCCounterpartyEndpoints parses IBC_COUNTERPARTY_ENDPOINTS, a
+// comma-separated list of chain_id=address gRPC endpoints of IBC counterparty
+// chains in the NODE_ENDPOINTS format, keyed by chain id.
+func (c Config) ParseIBCCounterpartyEndpoints() (map[string]NodeEndpoint, error) {
+	endpoints, err := c.parseEndpoints(c.IBCCounterpartyEndpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	byChainID := make(map[string]NodeEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		byChainID[endpoint.Name] = endpoint
+	}
+
+	return byChainID, nil
+}
+
+func (c Config) parseEndpoints(s string) ([]NodeEndpoint, error) {
+	endpoints := []NodeEndpoint{}
+	names := map[string]bool{}
+
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, addr, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		addr = strings.TrimSpace(addr)
+		if !ok || name == "" || addr == "" {
+			return nil, configError(fmt.Sprintf("invalid endpoint %q", entry))
+		}
+
+		if names[name] {
+			return nil, configError(fmt.Sprintf("duplicate endpoint %q", name))
+		}
+		names[name] = true
+
+		endpoint := NodeEndpoint{Name: name, Addr: addr, TLS: c.TLS}
+		if rest, found := strings.CutPrefix(addr, "grpcs://"); found {
+			endpoint.Addr, endpoint.TLS = rest, true
+		} else if rest, found = strings.CutPrefix(addr, "grpc://"); found {
+			endpoint.Addr, endpoint.TLS = rest, false
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+// ParseWardenSpaceIDs parses WARDEN_SPACE_IDS, a comma-separated list of space
+// ids to export key and template details for.
+func (c Config) ParseWardenSpaceIDs() ([]uint64, error) {
+	ids := []uint64{}
+
+	for _, entry := range strings.Split(c.WardenSpaceIDs, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, err := strconv.ParseUint(entry, 10, 64)
+		if err != nil {
+			return nil, configError(fmt.Sprintf("invalid space id %q", entry))
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// ForNode returns a copy of the config connecting to the given node.
+func (c Config) ForNode(node NodeEndpoint) Config {
+	c.GRPCAddr = node.Addr
+	c.TLS = node.TLS
+
+	return c
+}
+
 func (c Config) GRPCConn() (*grpc.ClientConn, error) {
 	tlsConfig := &tls.Config{
 		MinVersion: tls.VersionTLS12,
@@ -127,7 +586,7 @@ func (c Config) GRPCConn() (*grpc.ClientConn, error) {
 		c.GRPCAddr,
 		transportCreds,
 		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(math.MaxInt64)),
+			grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize)),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:                time.Duration(c.Timeout),
 			Timeout:             time.Duration(c.Timeout),
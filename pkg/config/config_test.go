@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+)
+
+// TestParseAPIAccounts tests alias handling of comma-separated API key lists.
+func TestParseAPIAccounts(t *testing.T) {
+	accounts := ParseAPIAccounts("prod:sk-admin-abcdefghijklmnop, sk-admin-qrstuvwxyz0123456,,staging: key-12345678 ")
+
+	expected := []APIAccount{
+		{Name: "prod", Key: "sk-admin-abcdefghijklmnop", Aliased: true},
+		{Name: "sk-admin-qrs...456", Key: "sk-admin-qrstuvwxyz0123456"},
+		{Name: "staging", Key: "key-12345678", Aliased: true},
+	}
+
+	if len(accounts) != len(expected) {
+		t.Fatalf("ParseAPIAccounts() returned %d accounts, want %d", len(accounts), len(expected))
+	}
+
+	for i, want := range expected {
+		if accounts[i] != want {
+			t.Errorf("ParseAPIAccounts()[%d] = %+v, want %+v", i, accounts[i], want)
+		}
+	}
+}
+
+// TestValidateAPIKeys tests that API key lists reusing an alias or a key are
+// rejected.
+func TestValidateAPIKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"aliased", Config{OpenAIAPIKey: "prod:sk-1,staging:sk-2"}, false},
+		{"unaliased", Config{OpenAIAPIKey: "sk-1, sk-2,,"}, false},
+		{"same alias in different settings", Config{OpenAIAPIKey: "prod:sk-1", AnthropicAdminKey: "prod:sk-2"}, false},
+		{"duplicate alias", Config{OpenAIAPIKey: "prod:sk-1, prod :sk-2"}, true},
+		{"duplicate key", Config{VeniceAPIKey: "sk-1,sk-1"}, true},
+		{"duplicate aliased key", Config{AnthropicAdminKey: "prod:sk-1,staging:sk-1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validateAPIKeys()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAPIKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateGemini tests that GEMINI_METRICS requires a billing URL.
+func TestValidateGemini(t *testing.T) {
+	if err := (Config{GeminiMetrics: true}).validateGemini(); err == nil {
+		t.Error("expected an error without GEMINI_BILLING_URL")
+	}
+
+	if err := (Config{GeminiMetrics: true, GeminiBillingURL: "https://billing.example"}).validateGemini(); err != nil {
+		t.Errorf("validateGemini() error = %s", err)
+	}
+
+	if err := (Config{}).validateGemini(); err != nil {
+		t.Errorf("validateGemini() error = %s with GEMINI_METRICS disabled", err)
+	}
+}
+
+// TestParseNodeEndpoints tests that the scheme prefix overrides the default
+// TLS setting.
+func TestParseNodeEndpoints(t *testing.T) {
+	cfg := Config{
+		TLS:           true,
+		NodeEndpoints: "sentry-0=grpc://10.0.0.5:9090, public=grpc.wardenprotocol.org:443,archive=grpcs://archive:443",
+	}
+
+	nodes, err := cfg.ParseNodeEndpoints()
+	if err != nil {
+		t.Fatalf("ParseNodeEndpoints() error = %s", err)
+	}
+
+	expected := []NodeEndpoint{
+		{Name: "sentry-0", Addr: "10.0.0.5:9090", TLS: false},
+		{Name: "public", Addr: "grpc.wardenprotocol.org:443", TLS: true},
+		{Name: "archive", Addr: "archive:443", TLS: true},
+	}
+	if len(nodes) != len(expected) {
+		t.Fatalf("ParseNodeEndpoints() = %v, want %v", nodes, expected)
+	}
+	for i := range expected {
+		if nodes[i] != expected[i] {
+			t.Errorf("nodes[%d] = %+v, want %+v", i, nodes[i], expected[i])
+		}
+	}
+
+	cfg.NodeEndpoints = "sentry-0=a:1,sentry-0=b:1"
+	if _, err = cfg.ParseNodeEndpoints(); err == nil {
+		t.Error("expected an error for duplicate node names")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	"google.golang.org/grpc"
 
 	"github.com/warden-protocol/warden-exporter/pkg/config"
@@ -11,6 +13,13 @@ import (
 
 var errEndpoint = errors.New("grpc error")
 
+// gogoCodec encodes messages with their gogoproto methods, for requests such as
+// the Warden key queries whose enums cannot be encoded through protobuf
+// reflection.
+//
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var gogoCodec = grpc.ForceCodec(codec.NewProtoCodec(codectypes.NewInterfaceRegistry()).GRPCCodec())
+
 func endpointError(msg string) error {
 	return fmt.Errorf("%w: %s", errEndpoint, msg)
 }
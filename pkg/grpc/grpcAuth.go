@@ -22,3 +22,16 @@ func (c Client) Accounts(ctx context.Context) (uint64, error) {
 
 	return accounts.Pagination.Total, nil
 }
+
+// AccountSequence returns the sequence number of an account, the number of
+// transactions it has signed.
+func (c Client) AccountSequence(ctx context.Context, address string) (uint64, error) {
+	client := auth.NewQueryClient(c.conn)
+
+	resp, err := client.AccountInfo(ctx, &auth.QueryAccountInfoRequest{Address: address})
+	if err != nil {
+		return 0, endpointError(err.Error())
+	}
+
+	return resp.GetInfo().GetSequence(), nil
+}
@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	authz "cosmossdk.io/api/cosmos/authz/v1beta1"
+	queryv1beta1 "cosmossdk.io/api/cosmos/base/query/v1beta1"
+	stakingv1beta1 "cosmossdk.io/api/cosmos/staking/v1beta1"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const stakingMsgPrefix = "/cosmos.staking.v1beta1."
+
+// AuthzGrant is an authz grant given to an account.
+type AuthzGrant struct {
+	Granter string
+	// Authorization is the message type URL of generic and staking
+	// authorizations and the authorization type URL otherwise. A granter can
+	// only give one grant per message type.
+	Authorization string
+	// Expiration is nil when the grant does not expire.
+	Expiration *time.Time
+}
+
+// AuthzGrants returns the authz grants given to grantee.
+func (c Client) AuthzGrants(ctx context.Context, grantee string) ([]AuthzGrant, error) {
+	var key []byte
+
+	client := authz.NewQueryClient(c.conn)
+	grants := []AuthzGrant{}
+
+	for {
+		resp, err := client.GranteeGrants(ctx, &authz.QueryGranteeGrantsRequest{
+			Grantee:    grantee,
+			Pagination: &queryv1beta1.PageRequest{Key: key, Limit: requestPageLimit},
+		})
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		for _, g := range resp.GetGrants() {
+			grant := AuthzGrant{
+				Granter:       g.GetGranter(),
+				Authorization: authorizationMsgType(g.GetAuthorization()),
+			}
+
+			if g.GetExpiration() != nil {
+				expiration := g.GetExpiration().AsTime()
+				grant.Expiration = &expiration
+			}
+
+			grants = append(grants, grant)
+		}
+
+		key = resp.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			return grants, nil
+		}
+	}
+}
+
+// authorizationMsgType returns the message type URL an authorization grants,
+// or its own type URL when that is not known.
+func authorizationMsgType(authorization *anypb.Any) string {
+	generic := &authz.GenericAuthorization{}
+	if authorization.MessageIs(generic) && authorization.UnmarshalTo(generic) == nil {
+		return generic.GetMsg()
+	}
+
+	// Staking grants share one authorization type, their message type is
+	// set by the authorization type
+	stake := &stakingv1beta1.StakeAuthorization{}
+	if authorization.MessageIs(stake) && authorization.UnmarshalTo(stake) == nil {
+		if msgType := stakeMsgType(stake.GetAuthorizationType()); msgType != "" {
+			return msgType
+		}
+	}
+
+	return authorization.GetTypeUrl()
+}
+
+// stakeMsgType returns the message type URL a staking authorization type
+// grants, or an empty string for an unspecified type.
+func stakeMsgType(authorizationType stakingv1beta1.AuthorizationType) string {
+	switch authorizationType {
+	case stakingv1beta1.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE:
+		return stakingMsgPrefix + "MsgDelegate"
+	case stakingv1beta1.AuthorizationType_AUTHORIZATION_TYPE_UNDELEGATE:
+		return stakingMsgPrefix + "MsgUndelegate"
+	case stakingv1beta1.AuthorizationType_AUTHORIZATION_TYPE_REDELEGATE:
+		return stakingMsgPrefix + "MsgBeginRedelegate"
+	case stakingv1beta1.AuthorizationType_AUTHORIZATION_TYPE_CANCEL_UNBONDING_DELEGATION:
+		return stakingMsgPrefix + "MsgCancelUnbondingDelegation"
+	default:
+		return ""
+	}
+}
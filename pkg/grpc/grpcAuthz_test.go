@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authz "cosmossdk.io/api/cosmos/authz/v1beta1"
+	bankv1beta1 "cosmossdk.io/api/cosmos/bank/v1beta1"
+	queryv1beta1 "cosmossdk.io/api/cosmos/base/query/v1beta1"
+	stakingv1beta1 "cosmossdk.io/api/cosmos/staking/v1beta1"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc/grpctest"
+)
+
+// authzServer serves the grants of a grantee in pages of two grants.
+type authzServer struct {
+	authz.UnimplementedQueryServer
+	grants []*authz.GrantAuthorization
+}
+
+func (s *authzServer) GranteeGrants(
+	_ context.Context,
+	req *authz.QueryGranteeGrantsRequest,
+) (*authz.QueryGranteeGrantsResponse, error) {
+	if string(req.GetPagination().GetKey()) == "next" {
+		return &authz.QueryGranteeGrantsResponse{
+			Grants:     s.grants[2:],
+			Pagination: &queryv1beta1.PageResponse{},
+		}, nil
+	}
+
+	return &authz.QueryGranteeGrantsResponse{
+		Grants:     s.grants[:2],
+		Pagination: &queryv1beta1.PageResponse{NextKey: []byte("next")},
+	}, nil
+}
+
+// newAuthorization packs an authorization with a type URL like the chain's.
+func newAuthorization(t *testing.T, authorization proto.Message) *anypb.Any {
+	t.Helper()
+
+	a, err := anypb.New(authorization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.TypeUrl = "/" + string(proto.MessageName(authorization))
+
+	return a
+}
+
+// TestAuthzGrants tests that grants are paged through and that generic and
+// staking authorizations are reported by the message type they grant, so the
+// staking grants of one granter do not share an authorization.
+func TestAuthzGrants(t *testing.T) {
+	expiration := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	granter := "warden1granter"
+
+	stake := func(authorizationType stakingv1beta1.AuthorizationType) *authz.GrantAuthorization {
+		return &authz.GrantAuthorization{
+			Granter: granter,
+			Authorization: newAuthorization(t, &stakingv1beta1.StakeAuthorization{
+				AuthorizationType: authorizationType,
+			}),
+			Expiration: timestamppb.New(expiration),
+		}
+	}
+
+	srv := &authzServer{grants: []*authz.GrantAuthorization{
+		{
+			Granter:       granter,
+			Authorization: newAuthorization(t, &authz.GenericAuthorization{Msg: "/warden.act.v1beta1.MsgNewAction"}),
+			Expiration:    timestamppb.New(expiration),
+		},
+		stake(stakingv1beta1.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE),
+		stake(stakingv1beta1.AuthorizationType_AUTHORIZATION_TYPE_UNDELEGATE),
+		{
+			Granter:       granter,
+			Authorization: newAuthorization(t, &bankv1beta1.SendAuthorization{}),
+		},
+	}}
+
+	addr := grpctest.NewServer(t, func(server *googlegrpc.Server) {
+		authz.RegisterQueryServer(server, srv)
+	})
+
+	client, err := NewClient(config.Config{GRPCAddr: addr})
+	if err != nil {
+		t.Fatalf("error creating client: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = client.CloseConn()
+	})
+
+	grants, err := client.AuthzGrants(context.Background(), "warden1grantee")
+	if err != nil {
+		t.Fatalf("AuthzGrants() error = %s", err)
+	}
+
+	want := []string{
+		"/warden.act.v1beta1.MsgNewAction",
+		"/cosmos.staking.v1beta1.MsgDelegate",
+		"/cosmos.staking.v1beta1.MsgUndelegate",
+		"/cosmos.bank.v1beta1.SendAuthorization",
+	}
+	if len(grants) != len(want) {
+		t.Fatalf("AuthzGrants() returned %d grants, want %d", len(grants), len(want))
+	}
+
+	for i, grant := range grants {
+		if grant.Granter != granter || grant.Authorization != want[i] {
+			t.Errorf("grant %d = %s from %s, want %s from %s", i, grant.Authorization, grant.Granter, want[i], granter)
+		}
+	}
+
+	if grants[1].Expiration == nil || !grants[1].Expiration.Equal(expiration) {
+		t.Errorf("expiration = %v, want %s", grants[1].Expiration, expiration)
+	}
+	if grants[3].Expiration != nil {
+		t.Errorf("expiration = %v, want none", grants[3].Expiration)
+	}
+}
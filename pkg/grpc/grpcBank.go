@@ -33,3 +33,18 @@ func (c Client) TotalSupply(ctx context.Context, denom string) (string, error) {
 	// The Amount field in the SDK types is math.Int, we need to convert to string
 	return resp.Amount.Amount.String(), nil
 }
+
+// SpendableBalance returns the balance of address that is neither locked by
+// vesting nor delegated.
+func (c Client) SpendableBalance(ctx context.Context, address, denom string) (math.Int, error) {
+	client := bank.NewQueryClient(c.conn)
+
+	req := bank.QuerySpendableBalanceByDenomRequest{Address: address, Denom: denom}
+
+	balance, err := client.SpendableBalanceByDenom(ctx, &req)
+	if err != nil {
+		return math.Int{}, endpointError(err.Error())
+	}
+
+	return balance.Balance.Amount, nil
+}
@@ -0,0 +1,253 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	base "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	txv1beta1 "cosmossdk.io/api/cosmos/tx/v1beta1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+// BlockStats holds what the exporter needs from a block, so a window of
+// blocks is fetched once and shared by all block metrics.
+type BlockStats struct {
+	Height int64
+	Time   time.Time
+	// Proposer is the valcons address of the block proposer, it is empty
+	// when the address could not be converted.
+	Proposer string
+	TxCount  int
+	// Size is the protobuf encoded size of the block in bytes.
+	Size int
+	// MsgTypes holds the message type URLs of every transaction of the block,
+	// in block order. It is nil for transactions that are not Cosmos
+	// transactions, such as the oracle data injected by the proposer.
+	MsgTypes [][]string
+	// FirstTx is the first transaction of the block, where the proposer
+	// injects the oracle data.
+	FirstTx []byte
+}
+
+// RecentBlocks returns the blocks from latest height - blockCount to the
+// latest height, in ascending height order. Blocks that cannot be fetched are
+// skipped.
+func RecentBlocks(ctx context.Context, cfg config.Config, blockCount int64) ([]BlockStats, error) {
+	client, err := NewClient(cfg)
+	if err != nil {
+		log.Error(err.Error())
+
+		return nil, endpointError(err.Error())
+	}
+
+	baseClient := base.NewServiceClient(client.conn)
+
+	// Get latest block height first
+	latestReq := &base.GetLatestBlockRequest{}
+	latestResp, err := baseClient.GetLatestBlock(ctx, latestReq)
+
+	defer func() {
+		if tempErr := client.conn.Close(); tempErr != nil {
+			log.Error(tempErr.Error())
+		}
+	}()
+
+	if err != nil {
+		log.Error(err.Error())
+
+		return nil, endpointError(err.Error())
+	}
+
+	latestHeight := latestResp.GetBlock().GetHeader().GetHeight()
+	startHeight := latestHeight - blockCount
+	startHeight = max(startHeight, 1)
+
+	blocks := make([]BlockStats, 0, latestHeight-startHeight+1)
+
+	for height := startHeight; height <= latestHeight; height++ {
+		var blockResp *base.GetBlockByHeightResponse
+		blockReq := &base.GetBlockByHeightRequest{Height: height}
+		blockResp, err = baseClient.GetBlockByHeight(ctx, blockReq)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Error fetching block %d: %s", height, err.Error()))
+			continue
+		}
+
+		block := blockResp.GetBlock()
+		if block == nil || block.GetHeader() == nil {
+			continue
+		}
+
+		stats := BlockStats{
+			Height:  height,
+			Time:    block.GetHeader().GetTime().AsTime(),
+			TxCount: len(block.GetData().GetTxs()),
+			Size:    proto.Size(block),
+		}
+
+		for _, tx := range block.GetData().GetTxs() {
+			stats.MsgTypes = append(stats.MsgTypes, txMsgTypes(tx))
+		}
+		if stats.TxCount > 0 {
+			stats.FirstTx = block.GetData().GetTxs()[0]
+		}
+
+		// Convert proposer address bytes to valcons address
+		stats.Proposer, err = bech32.ConvertAndEncode(prefix+valConsStr, block.GetHeader().GetProposerAddress())
+		if err != nil {
+			log.Debug(
+				fmt.Sprintf(
+					"Error converting proposer address at height %d: %s",
+					height,
+					err.Error(),
+				),
+			)
+			stats.Proposer = ""
+		}
+
+		blocks = append(blocks, stats)
+	}
+
+	log.Debug(fmt.Sprintf("Fetched %d blocks from %d to %d", len(blocks), startHeight, latestHeight))
+
+	return blocks, nil
+}
+
+// LatestBlock returns the height and time of the latest block of the node
+// cfg connects to.
+func LatestBlock(ctx context.Context, cfg config.Config) (BlockStats, error) {
+	client, err := NewClient(cfg)
+	if err != nil {
+		log.Error(err.Error())
+
+		return BlockStats{}, endpointError(err.Error())
+	}
+
+	baseClient := base.NewServiceClient(client.conn)
+	blockResp, err := baseClient.GetLatestBlock(ctx, &base.GetLatestBlockRequest{})
+
+	defer func() {
+		if tempErr := client.conn.Close(); tempErr != nil {
+			log.Error(tempErr.Error())
+		}
+	}()
+
+	if err != nil {
+		return BlockStats{}, endpointError(err.Error())
+	}
+
+	header := blockResp.GetBlock().GetHeader()
+	if header == nil {
+		return BlockStats{}, endpointError("latest block has no header")
+	}
+
+	return BlockStats{
+		Height:  header.GetHeight(),
+		Time:    header.GetTime().AsTime(),
+		TxCount: len(blockResp.GetBlock().GetData().GetTxs()),
+		Size:    proto.Size(blockResp.GetBlock()),
+	}, nil
+}
+
+// txMsgTypes returns the message type URLs of a raw transaction, or nil when
+// it is not a Cosmos transaction.
+func txMsgTypes(tx []byte) []string {
+	var raw txv1beta1.TxRaw
+	if err := proto.Unmarshal(tx, &raw); err != nil {
+		return nil
+	}
+
+	var body txv1beta1.TxBody
+	if err := proto.Unmarshal(raw.GetBodyBytes(), &body); err != nil {
+		return nil
+	}
+
+	msgTypes := make([]string, 0, len(body.GetMessages()))
+	for _, msg := range body.GetMessages() {
+		if msg.GetTypeUrl() == "" {
+			return nil
+		}
+		msgTypes = append(msgTypes, msg.GetTypeUrl())
+	}
+
+	if len(msgTypes) == 0 {
+		return nil
+	}
+
+	return msgTypes
+}
+
+// BlockProposers counts the blocks proposed by each validator within the last
+// blockCount blocks, keyed by valcons address.
+func BlockProposers(blocks []BlockStats, blockCount int64) map[string]int64 {
+	proposerCounts := make(map[string]int64)
+	if len(blocks) == 0 {
+		return proposerCounts
+	}
+
+	startHeight := blocks[len(blocks)-1].Height - blockCount
+	for _, block := range blocks {
+		if block.Height < startHeight || block.Proposer == "" {
+			continue
+		}
+		proposerCounts[block.Proposer]++
+	}
+
+	log.Debug(fmt.Sprintf("Found %d unique proposers", len(proposerCounts)))
+
+	return proposerCounts
+}
+
+// AverageBlockTime returns the average block time in seconds over the last
+// sampleSize blocks, measured between the oldest and the latest block.
+func AverageBlockTime(blocks []BlockStats, sampleSize int64) (float64, error) {
+	if len(blocks) < 2 {
+		return 0, endpointError("invalid block count")
+	}
+
+	end := blocks[len(blocks)-1]
+	start := blocks[0]
+	for _, block := range blocks {
+		if block.Height >= end.Height-sampleSize {
+			start = block
+			break
+		}
+	}
+
+	blockCount := float64(end.Height - start.Height)
+	if blockCount == 0 {
+		return 0, endpointError("invalid block count")
+	}
+
+	avgBlockTime := end.Time.Sub(start.Time).Seconds() / blockCount
+
+	log.Debug(
+		fmt.Sprintf(
+			"Average block time: %.2f seconds (sampled %d blocks)",
+			avgBlockTime,
+			int64(blockCount),
+		),
+	)
+
+	return avgBlockTime, nil
+}
+
+// BlockIntervals returns the time in seconds between consecutive blocks.
+// Intervals spanning a block that could not be fetched are left out.
+func BlockIntervals(blocks []BlockStats) []float64 {
+	intervals := []float64{}
+
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].Height != blocks[i-1].Height+1 {
+			continue
+		}
+		intervals = append(intervals, blocks[i].Time.Sub(blocks[i-1].Time).Seconds())
+	}
+
+	return intervals
+}
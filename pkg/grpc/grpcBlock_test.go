@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	txv1beta1 "cosmossdk.io/api/cosmos/tx/v1beta1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func testBlocks() []BlockStats {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return []BlockStats{
+		{Height: 10, Time: start, Proposer: "wardenvalcons1a"},
+		{Height: 11, Time: start.Add(2 * time.Second), Proposer: "wardenvalcons1b"},
+		// Height 12 could not be fetched.
+		{Height: 13, Time: start.Add(9 * time.Second), Proposer: "wardenvalcons1a"},
+		{Height: 14, Time: start.Add(12 * time.Second), Proposer: ""},
+	}
+}
+
+// TestBlockIntervals tests that intervals are only measured between
+// consecutive heights.
+func TestBlockIntervals(t *testing.T) {
+	intervals := BlockIntervals(testBlocks())
+	if len(intervals) != 2 || intervals[0] != 2 || intervals[1] != 3 {
+		t.Errorf("BlockIntervals() = %v, want [2 3]", intervals)
+	}
+}
+
+// TestAverageBlockTime tests that the average is measured over the sample
+// and falls back to the whole window when it is shorter.
+func TestAverageBlockTime(t *testing.T) {
+	tests := []struct {
+		sampleSize int64
+		want       float64
+	}{
+		{sampleSize: 1, want: 3},
+		{sampleSize: 3, want: 10.0 / 3},
+		{sampleSize: 100, want: 3},
+	}
+
+	for _, tt := range tests {
+		got, err := AverageBlockTime(testBlocks(), tt.sampleSize)
+		if err != nil {
+			t.Fatalf("AverageBlockTime(%d) error = %s", tt.sampleSize, err)
+		}
+		if got != tt.want {
+			t.Errorf("AverageBlockTime(%d) = %v, want %v", tt.sampleSize, got, tt.want)
+		}
+	}
+
+	if _, err := AverageBlockTime(testBlocks()[:1], 100); err == nil {
+		t.Error("expected an error for a single block")
+	}
+}
+
+// TestBlockProposers tests that only blocks within the window with a known
+// proposer are counted.
+func TestBlockProposers(t *testing.T) {
+	counts := BlockProposers(testBlocks(), 3)
+	if len(counts) != 2 || counts["wardenvalcons1a"] != 1 || counts["wardenvalcons1b"] != 1 {
+		t.Errorf("BlockProposers() = %v", counts)
+	}
+}
+
+// TestTxMsgTypes tests that message type URLs are read from Cosmos
+// transactions and that other transactions are left out.
+func TestTxMsgTypes(t *testing.T) {
+	body, err := proto.Marshal(&txv1beta1.TxBody{Messages: []*anypb.Any{
+		{TypeUrl: "/cosmos.bank.v1beta1.MsgSend"},
+		{TypeUrl: "/warden.act.v1beta1.MsgNewAction"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := proto.Marshal(&txv1beta1.TxRaw{BodyBytes: body})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/cosmos.bank.v1beta1.MsgSend", "/warden.act.v1beta1.MsgNewAction"}
+	if got := txMsgTypes(tx); !slices.Equal(got, want) {
+		t.Errorf("txMsgTypes() = %v, want %v", got, want)
+	}
+
+	// Oracle data injected by the proposer is zstd compressed
+	if got := txMsgTypes([]byte{0x28, 0xb5, 0x2f, 0xfd, 0x04, 0x00}); got != nil {
+		t.Errorf("txMsgTypes() = %v, want nil", got)
+	}
+}
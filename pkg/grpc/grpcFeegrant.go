@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	queryv1beta1 "cosmossdk.io/api/cosmos/base/query/v1beta1"
+	basev1beta1 "cosmossdk.io/api/cosmos/base/v1beta1"
+	feegrant "cosmossdk.io/api/cosmos/feegrant/v1beta1"
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// FeeAllowance is a fee grant given to an account.
+type FeeAllowance struct {
+	Granter string
+	// SpendLimit is what is left of the total spend limit, nil when there is
+	// no limit.
+	SpendLimit sdk.Coins
+	// PeriodCanSpend is what is left to spend in the current period of a
+	// periodic allowance.
+	PeriodCanSpend sdk.Coins
+	// Expiration is nil when the allowance does not expire.
+	Expiration *time.Time
+}
+
+// FeeAllowances returns the fee grants given to grantee.
+func (c Client) FeeAllowances(ctx context.Context, grantee string) ([]FeeAllowance, error) {
+	var key []byte
+
+	client := feegrant.NewQueryClient(c.conn)
+	allowances := []FeeAllowance{}
+
+	for {
+		resp, err := client.Allowances(ctx, &feegrant.QueryAllowancesRequest{
+			Grantee:    grantee,
+			Pagination: &queryv1beta1.PageRequest{Key: key, Limit: requestPageLimit},
+		})
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		for _, grant := range resp.GetAllowances() {
+			allowance := FeeAllowance{Granter: grant.GetGranter()}
+			if err = allowance.unpack(grant.GetAllowance()); err != nil {
+				return nil, endpointError(err.Error())
+			}
+			allowances = append(allowances, allowance)
+		}
+
+		key = resp.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			return allowances, nil
+		}
+	}
+}
+
+// unpack reads the limits and expiration of an allowance, unwrapping allowed
+// message allowances.
+func (a *FeeAllowance) unpack(allowance *anypb.Any) error {
+	msg, err := allowance.UnmarshalNew()
+	if err != nil {
+		return fmt.Errorf("error decoding allowance %s: %w", allowance.GetTypeUrl(), err)
+	}
+
+	switch v := msg.(type) {
+	case *feegrant.BasicAllowance:
+		return a.setBasic(v)
+	case *feegrant.PeriodicAllowance:
+		if a.PeriodCanSpend, err = coins(v.GetPeriodCanSpend()); err != nil {
+			return err
+		}
+
+		return a.setBasic(v.GetBasic())
+	case *feegrant.AllowedMsgAllowance:
+		return a.unpack(v.GetAllowance())
+	default:
+		return fmt.Errorf("unknown allowance type %s", allowance.GetTypeUrl())
+	}
+}
+
+func (a *FeeAllowance) setBasic(basic *feegrant.BasicAllowance) error {
+	if basic.GetExpiration() != nil {
+		expiration := basic.GetExpiration().AsTime()
+		a.Expiration = &expiration
+	}
+
+	var err error
+	a.SpendLimit, err = coins(basic.GetSpendLimit())
+
+	return err
+}
+
+// coins converts coins of the API module types to SDK coins.
+func coins(apiCoins []*basev1beta1.Coin) (sdk.Coins, error) {
+	if len(apiCoins) == 0 {
+		return nil, nil
+	}
+
+	result := make(sdk.Coins, 0, len(apiCoins))
+	for _, coin := range apiCoins {
+		amount, ok := math.NewIntFromString(coin.GetAmount())
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %s%s", coin.GetAmount(), coin.GetDenom())
+		}
+		result = append(result, sdk.Coin{Denom: coin.GetDenom(), Amount: amount})
+	}
+
+	return result, nil
+}
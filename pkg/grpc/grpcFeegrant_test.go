@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	basev1beta1 "cosmossdk.io/api/cosmos/base/v1beta1"
+	feegrant "cosmossdk.io/api/cosmos/feegrant/v1beta1"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestFeeAllowanceUnpack tests that the limits and expiration of a periodic
+// allowance are read through an allowed message allowance.
+func TestFeeAllowanceUnpack(t *testing.T) {
+	expiration := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	periodic, err := anypb.New(&feegrant.PeriodicAllowance{
+		Basic: &feegrant.BasicAllowance{
+			SpendLimit: []*basev1beta1.Coin{{Denom: "award", Amount: "5000"}},
+			Expiration: timestamppb.New(expiration),
+		},
+		PeriodCanSpend: []*basev1beta1.Coin{{Denom: "award", Amount: "100"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowed, err := anypb.New(&feegrant.AllowedMsgAllowance{
+		Allowance:       periodic,
+		AllowedMessages: []string{"/warden.act.v1beta1.MsgNewAction"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowance := FeeAllowance{}
+	if err = allowance.unpack(allowed); err != nil {
+		t.Fatalf("unpack() error = %s", err)
+	}
+
+	if allowance.SpendLimit.String() != "5000award" || allowance.PeriodCanSpend.String() != "100award" {
+		t.Errorf("spend limit = %s, period can spend = %s", allowance.SpendLimit, allowance.PeriodCanSpend)
+	}
+
+	if allowance.Expiration == nil || !allowance.Expiration.Equal(expiration) {
+		t.Errorf("expiration = %v, want %s", allowance.Expiration, expiration)
+	}
+
+	// A basic allowance without limits has no spend limit and no expiration
+	basic, err := anypb.New(&feegrant.BasicAllowance{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowance = FeeAllowance{}
+	if err = allowance.unpack(basic); err != nil {
+		t.Fatalf("unpack() error = %s", err)
+	}
+	if allowance.SpendLimit != nil || allowance.Expiration != nil {
+		t.Errorf("unpack() = %+v, want no limit and no expiration", allowance)
+	}
+}
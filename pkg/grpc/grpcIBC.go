@@ -0,0 +1,281 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	connectiontypes "github.com/cosmos/ibc-go/v8/modules/core/03-connection/types"
+	channeltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	ibctm "github.com/cosmos/ibc-go/v8/modules/light-clients/07-tendermint"
+
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+const tendermintClientStateTypeURL = "/ibc.lightclients.tendermint.v1.ClientState"
+
+// IBCClient holds the state of an IBC light client.
+type IBCClient struct {
+	ClientID string
+	// ChainID is the chain the light client tracks.
+	ChainID        string
+	Status         string
+	LatestHeight   uint64
+	TrustingPeriod time.Duration
+	// LatestTimestamp is the time of the consensus state at LatestHeight.
+	LatestTimestamp time.Time
+}
+
+// ExpiresAt returns when the client expires if it is not updated.
+func (c IBCClient) ExpiresAt() time.Time {
+	return c.LatestTimestamp.Add(c.TrustingPeriod)
+}
+
+func (c Client) IBCChannels(ctx context.Context) ([]*channeltypes.IdentifiedChannel, error) {
+	channels := []*channeltypes.IdentifiedChannel{}
+	key := []byte{}
+	client := channeltypes.NewQueryClient(c.conn)
+
+	for {
+		request := &channeltypes.QueryChannelsRequest{Pagination: &query.PageRequest{Key: key}}
+
+		res, err := client.Channels(ctx, request)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		channels = append(channels, res.GetChannels()...)
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	log.Debug(fmt.Sprintf("IBC channels: %d", len(channels)))
+
+	return channels, nil
+}
+
+func (c Client) IBCConnections(ctx context.Context) ([]*connectiontypes.IdentifiedConnection, error) {
+	connections := []*connectiontypes.IdentifiedConnection{}
+	key := []byte{}
+	client := connectiontypes.NewQueryClient(c.conn)
+
+	for {
+		request := &connectiontypes.QueryConnectionsRequest{Pagination: &query.PageRequest{Key: key}}
+
+		res, err := client.Connections(ctx, request)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		connections = append(connections, res.GetConnections()...)
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	log.Debug(fmt.Sprintf("IBC connections: %d", len(connections)))
+
+	return connections, nil
+}
+
+// IBCPacketCommitments returns the sequences of the packets sent on a channel
+// that have not been acknowledged or timed out yet.
+func (c Client) IBCPacketCommitments(ctx context.Context, portID, channelID string) ([]uint64, error) {
+	sequences := []uint64{}
+	key := []byte{}
+	client := channeltypes.NewQueryClient(c.conn)
+
+	for {
+		request := &channeltypes.QueryPacketCommitmentsRequest{
+			PortId:     portID,
+			ChannelId:  channelID,
+			Pagination: &query.PageRequest{Key: key},
+		}
+
+		res, err := client.PacketCommitments(ctx, request)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		for _, commitment := range res.GetCommitments() {
+			sequences = append(sequences, commitment.Sequence)
+		}
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	return sequences, nil
+}
+
+// IBCPacketAcknowledgements returns which of the given packet commitment
+// sequences of the counterparty were received on a channel and acknowledged.
+// The query is limited to the given sequences, as a channel keeps the
+// acknowledgement of every packet it ever received.
+func (c Client) IBCPacketAcknowledgements(
+	ctx context.Context,
+	portID, channelID string,
+	commitments []uint64,
+) ([]uint64, error) {
+	sequences := []uint64{}
+	if len(commitments) == 0 {
+		return sequences, nil
+	}
+
+	key := []byte{}
+	client := channeltypes.NewQueryClient(c.conn)
+
+	for {
+		request := &channeltypes.QueryPacketAcknowledgementsRequest{
+			PortId:                    portID,
+			ChannelId:                 channelID,
+			Pagination:                &query.PageRequest{Key: key},
+			PacketCommitmentSequences: commitments,
+		}
+
+		res, err := client.PacketAcknowledgements(ctx, request)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		for _, ack := range res.GetAcknowledgements() {
+			sequences = append(sequences, ack.Sequence)
+		}
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	return sequences, nil
+}
+
+// IBCUnreceivedPackets returns which of the given packet commitment sequences
+// of the counterparty have not been received on the channel.
+func (c Client) IBCUnreceivedPackets(
+	ctx context.Context,
+	portID, channelID string,
+	sequences []uint64,
+) ([]uint64, error) {
+	if len(sequences) == 0 {
+		return []uint64{}, nil
+	}
+
+	client := channeltypes.NewQueryClient(c.conn)
+	res, err := client.UnreceivedPackets(ctx, &channeltypes.QueryUnreceivedPacketsRequest{
+		PortId:                    portID,
+		ChannelId:                 channelID,
+		PacketCommitmentSequences: sequences,
+	})
+	if err != nil {
+		return nil, endpointError(err.Error())
+	}
+
+	return res.GetSequences(), nil
+}
+
+// IBCUnreceivedAcks returns which of the given acknowledgement sequences of the
+// counterparty have not been received on the channel.
+func (c Client) IBCUnreceivedAcks(
+	ctx context.Context,
+	portID, channelID string,
+	sequences []uint64,
+) ([]uint64, error) {
+	if len(sequences) == 0 {
+		return []uint64{}, nil
+	}
+
+	client := channeltypes.NewQueryClient(c.conn)
+	res, err := client.UnreceivedAcks(ctx, &channeltypes.QueryUnreceivedAcksRequest{
+		PortId:             portID,
+		ChannelId:          channelID,
+		PacketAckSequences: sequences,
+	})
+	if err != nil {
+		return nil, endpointError(err.Error())
+	}
+
+	return res.GetSequences(), nil
+}
+
+// IBCClients returns the 07-tendermint light clients with their status and the
+// timestamp of their latest consensus state. Other client types are skipped.
+func (c Client) IBCClients(ctx context.Context) ([]IBCClient, error) {
+	clients := []IBCClient{}
+	key := []byte{}
+	client := clienttypes.NewQueryClient(c.conn)
+
+	for {
+		request := &clienttypes.QueryClientStatesRequest{Pagination: &query.PageRequest{Key: key}}
+
+		res, err := client.ClientStates(ctx, request)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		for _, identified := range res.GetClientStates() {
+			if identified.GetClientState().GetTypeUrl() != tendermintClientStateTypeURL {
+				continue
+			}
+
+			var clientState ibctm.ClientState
+			if err = clientState.Unmarshal(identified.GetClientState().GetValue()); err != nil {
+				return nil, endpointError(err.Error())
+			}
+
+			ibcClient := IBCClient{
+				ClientID:       identified.GetClientId(),
+				ChainID:        clientState.GetChainID(),
+				LatestHeight:   clientState.LatestHeight.GetRevisionHeight(),
+				TrustingPeriod: clientState.TrustingPeriod,
+				Status:         string(ibcexported.Unknown),
+			}
+
+			statusRes, statusErr := client.ClientStatus(ctx, &clienttypes.QueryClientStatusRequest{
+				ClientId: ibcClient.ClientID,
+			})
+			if statusErr != nil {
+				log.Debug(fmt.Sprintf("Error fetching status of client %s: %s", ibcClient.ClientID, statusErr))
+			} else {
+				ibcClient.Status = statusRes.GetStatus()
+			}
+
+			consensusRes, consensusErr := client.ConsensusState(ctx, &clienttypes.QueryConsensusStateRequest{
+				ClientId:       ibcClient.ClientID,
+				RevisionNumber: clientState.LatestHeight.GetRevisionNumber(),
+				RevisionHeight: clientState.LatestHeight.GetRevisionHeight(),
+			})
+			if consensusErr != nil {
+				return nil, endpointError(consensusErr.Error())
+			}
+
+			var consensusState ibctm.ConsensusState
+			if err = consensusState.Unmarshal(consensusRes.GetConsensusState().GetValue()); err != nil {
+				return nil, endpointError(err.Error())
+			}
+			ibcClient.LatestTimestamp = consensusState.Timestamp
+
+			clients = append(clients, ibcClient)
+		}
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	log.Debug(fmt.Sprintf("IBC clients: %d", len(clients)))
+
+	return clients, nil
+}
@@ -0,0 +1,176 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	compression "github.com/skip-mev/slinky/abci/strategies/codec"
+	oracle "github.com/skip-mev/slinky/x/oracle/types"
+
+	log "github.com/warden-protocol/warden-exporter/pkg/logger"
+)
+
+// OraclePrice is the latest price of an oracle currency pair.
+type OraclePrice struct {
+	Pair     string
+	Decimals uint64
+	// Price is nil when the pair has not been updated yet.
+	Price       *math.Int
+	BlockHeight uint64
+	BlockTime   time.Time
+}
+
+// OracleVotes holds the oracle vote extensions of every validator over a
+// window of blocks.
+type OracleVotes struct {
+	// Blocks is the number of blocks with oracle data in the window.
+	Blocks int
+	// MissingBlocks is the number of blocks in the window that could not be
+	// fetched.
+	MissingBlocks int
+	// LatestHeight is the height of the latest block in the window.
+	LatestHeight int64
+	// Votes counts per valcons address the blocks the validator signed and
+	// the blocks its vote extension contained prices.
+	Votes map[string]ValidatorOracleVotes
+}
+
+// ValidatorOracleVotes counts the oracle votes of a validator.
+type ValidatorOracleVotes struct {
+	Signed     int
+	WithPrices int
+}
+
+// The codecs Warden uses for the oracle data and vote extensions of slinky.
+//
+//nolint:gochecknoglobals // this is needed as it's used in multiple places
+var (
+	extCommitCodec = compression.NewCompressionExtendedCommitCodec(
+		compression.NewDefaultExtendedCommitCodec(),
+		compression.NewZStdCompressor(),
+	)
+	voteExtensionCodec = compression.NewCompressionVoteExtensionCodec(
+		compression.NewDefaultVoteExtensionCodec(),
+		compression.NewZLibCompressor(),
+	)
+)
+
+// OraclePrices returns the latest price of every oracle currency pair.
+func (c Client) OraclePrices(ctx context.Context) ([]OraclePrice, error) {
+	client := oracle.NewQueryClient(c.conn)
+
+	pairsRes, err := client.GetAllCurrencyPairs(ctx, &oracle.GetAllCurrencyPairsRequest{})
+	if err != nil {
+		return nil, endpointError(err.Error())
+	}
+
+	pairs := make([]string, 0, len(pairsRes.GetCurrencyPairs()))
+	for _, pair := range pairsRes.GetCurrencyPairs() {
+		pairs = append(pairs, pair.String())
+	}
+
+	if len(pairs) == 0 {
+		return []OraclePrice{}, nil
+	}
+
+	pricesRes, err := client.GetPrices(ctx, &oracle.GetPricesRequest{CurrencyPairIds: pairs})
+	if err != nil {
+		return nil, endpointError(err.Error())
+	}
+
+	if len(pricesRes.GetPrices()) != len(pairs) {
+		return nil, endpointError(
+			fmt.Sprintf("expected %d oracle prices, got %d", len(pairs), len(pricesRes.GetPrices())),
+		)
+	}
+
+	// Prices are returned in the order of the requested pairs
+	prices := make([]OraclePrice, 0, len(pairs))
+	for i, res := range pricesRes.GetPrices() {
+		price := OraclePrice{Pair: pairs[i], Decimals: res.GetDecimals()}
+		if quote := res.GetPrice(); quote != nil {
+			price.Price = &quote.Price
+			price.BlockHeight = quote.BlockHeight
+			price.BlockTime = quote.BlockTimestamp
+		}
+		prices = append(prices, price)
+	}
+
+	log.Debug(fmt.Sprintf("Oracle prices: %d", len(prices)))
+
+	return prices, nil
+}
+
+// RecentOracleVotes decodes the oracle data the proposer injects as the first
+// transaction of each of the last blockCount blocks, as returned by
+// RecentBlocks. Blocks without oracle data, e.g. before vote extensions were
+// enabled, are skipped.
+func RecentOracleVotes(blocks []BlockStats, blockCount int64) OracleVotes {
+	votes := OracleVotes{Votes: map[string]ValidatorOracleVotes{}}
+	if len(blocks) == 0 {
+		votes.MissingBlocks = int(blockCount)
+		return votes
+	}
+
+	votes.LatestHeight = blocks[len(blocks)-1].Height
+	startHeight := max(votes.LatestHeight-blockCount+1, 1)
+
+	fetched := 0
+	for _, block := range blocks {
+		if block.Height < startHeight {
+			continue
+		}
+		fetched++
+
+		if len(block.FirstTx) == 0 {
+			continue
+		}
+
+		if err := votes.add(block.FirstTx); err != nil {
+			log.Debug(fmt.Sprintf("Block %d has no oracle data: %s", block.Height, err))
+		}
+	}
+	votes.MissingBlocks = int(votes.LatestHeight-startHeight+1) - fetched
+
+	log.Debug(fmt.Sprintf("Decoded oracle data of %d blocks", votes.Blocks))
+
+	return votes
+}
+
+// add counts the votes of the oracle data injected as first transaction of a
+// block.
+func (v *OracleVotes) add(tx []byte) error {
+	commitInfo, err := extCommitCodec.Decode(tx)
+	if err != nil {
+		return err
+	}
+
+	v.Blocks++
+
+	for _, vote := range commitInfo.Votes {
+		valcons, convertErr := bech32.ConvertAndEncode(prefix+valConsStr, vote.Validator.Address)
+		if convertErr != nil {
+			log.Debug(fmt.Sprintf("Error converting validator address: %s", convertErr))
+			continue
+		}
+
+		validatorVotes := v.Votes[valcons]
+		if vote.BlockIdFlag == cmtproto.BlockIDFlagCommit {
+			validatorVotes.Signed++
+
+			// A validator whose sidecar is down still signs, but its vote
+			// extension carries no prices
+			ve, veErr := voteExtensionCodec.Decode(vote.VoteExtension)
+			if veErr == nil && len(ve.Prices) > 0 {
+				validatorVotes.WithPrices++
+			}
+		}
+		v.Votes[valcons] = validatorVotes
+	}
+
+	return nil
+}
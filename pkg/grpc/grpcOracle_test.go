@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"testing"
+
+	cmtabci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	vetypes "github.com/skip-mev/slinky/abci/ve/types"
+)
+
+func oracleVote(t *testing.T, address byte, flag cmtproto.BlockIDFlag, prices map[uint64][]byte) cmtabci.ExtendedVoteInfo {
+	t.Helper()
+
+	ve, err := voteExtensionCodec.Encode(vetypes.OracleVoteExtension{Prices: prices})
+	if err != nil {
+		t.Fatalf("error encoding vote extension: %s", err)
+	}
+
+	return cmtabci.ExtendedVoteInfo{
+		Validator:     cmtabci.Validator{Address: []byte{address}, Power: 1},
+		VoteExtension: ve,
+		BlockIdFlag:   flag,
+	}
+}
+
+// TestOracleVotesAdd tests that only committed votes with prices count as
+// price votes and that transactions without oracle data are rejected.
+func TestOracleVotesAdd(t *testing.T) {
+	prices := map[uint64][]byte{0: {1}}
+
+	tx, err := extCommitCodec.Encode(cmtabci.ExtendedCommitInfo{
+		Votes: []cmtabci.ExtendedVoteInfo{
+			oracleVote(t, 1, cmtproto.BlockIDFlagCommit, prices),
+			// Sidecar down: the validator signs without prices
+			oracleVote(t, 2, cmtproto.BlockIDFlagCommit, nil),
+			oracleVote(t, 3, cmtproto.BlockIDFlagAbsent, prices),
+		},
+	})
+	if err != nil {
+		t.Fatalf("error encoding commit info: %s", err)
+	}
+
+	votes := OracleVotes{Votes: map[string]ValidatorOracleVotes{}}
+	for range 2 {
+		if err = votes.add(tx); err != nil {
+			t.Fatalf("add() error = %s", err)
+		}
+	}
+
+	if err = votes.add([]byte("not oracle data")); err == nil {
+		t.Error("expected an error for a regular transaction")
+	}
+
+	if votes.Blocks != 2 {
+		t.Errorf("Blocks = %d, want 2", votes.Blocks)
+	}
+
+	want := map[byte]ValidatorOracleVotes{
+		1: {Signed: 2, WithPrices: 2},
+		2: {Signed: 2, WithPrices: 0},
+		3: {Signed: 0, WithPrices: 0},
+	}
+
+	for address, wantVotes := range want {
+		valcons, err := bech32.ConvertAndEncode(prefix+valConsStr, []byte{address})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, ok := votes.Votes[valcons]
+		if !ok || got != wantVotes {
+			t.Errorf("Votes[%s] = %+v, want %+v", valcons, got, wantVotes)
+		}
+	}
+}
+
+// TestRecentOracleVotes tests that only the last blockCount blocks are
+// decoded and that blocks missing from the window are counted.
+func TestRecentOracleVotes(t *testing.T) {
+	tx, err := extCommitCodec.Encode(cmtabci.ExtendedCommitInfo{
+		Votes: []cmtabci.ExtendedVoteInfo{
+			oracleVote(t, 1, cmtproto.BlockIDFlagCommit, map[uint64][]byte{0: {1}}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("error encoding commit info: %s", err)
+	}
+
+	// Block 12 could not be fetched and block 13 has no transactions
+	blocks := []BlockStats{
+		{Height: 10, FirstTx: tx},
+		{Height: 11, FirstTx: tx},
+		{Height: 13},
+		{Height: 14, FirstTx: tx},
+	}
+
+	votes := RecentOracleVotes(blocks, 4)
+	if votes.LatestHeight != 14 || votes.Blocks != 2 || votes.MissingBlocks != 1 {
+		t.Errorf("got latest height %d, %d blocks and %d missing blocks, want 14, 2 and 1",
+			votes.LatestHeight, votes.Blocks, votes.MissingBlocks)
+	}
+
+	if votes = RecentOracleVotes(nil, 4); votes.MissingBlocks != 4 {
+		t.Errorf("MissingBlocks = %d without blocks, want 4", votes.MissingBlocks)
+	}
+}
@@ -233,156 +233,6 @@ func LatestBlockHeight(ctx context.Context, cfg config.Config) (int64, error) {
 	return height, nil
 }
 
-func BlockProposers(
-	ctx context.Context,
-	cfg config.Config,
-	blockCount int64,
-) (map[string]int64, error) {
-	client, err := NewClient(cfg)
-	if err != nil {
-		log.Error(err.Error())
-
-		return nil, endpointError(err.Error())
-	}
-
-	baseClient := base.NewServiceClient(client.conn)
-
-	// Get latest block height first
-	latestReq := &base.GetLatestBlockRequest{}
-	latestResp, err := baseClient.GetLatestBlock(ctx, latestReq)
-
-	defer func() {
-		if tempErr := client.conn.Close(); tempErr != nil {
-			log.Error(tempErr.Error())
-		}
-	}()
-
-	if err != nil {
-		log.Error(err.Error())
-
-		return nil, endpointError(err.Error())
-	}
-
-	latestHeight := latestResp.GetBlock().Header.Height
-	startHeight := latestHeight - blockCount
-	startHeight = max(startHeight, 1)
-
-	// Count proposers
-	proposerCounts := make(map[string]int64)
-
-	for height := startHeight; height <= latestHeight; height++ {
-		var blockResp *base.GetBlockByHeightResponse
-		blockReq := &base.GetBlockByHeightRequest{Height: height}
-		blockResp, err = baseClient.GetBlockByHeight(ctx, blockReq)
-		if err != nil {
-			log.Debug(fmt.Sprintf("Error fetching block %d: %s", height, err.Error()))
-			continue
-		}
-
-		if blockResp.GetBlock() != nil && blockResp.GetBlock().Header != nil {
-			var consAddr string
-			proposerAddr := blockResp.GetBlock().Header.ProposerAddress
-
-			// Convert proposer address bytes to valcons address
-			consAddr, err = bech32.ConvertAndEncode(prefix+valConsStr, proposerAddr)
-			if err != nil {
-				log.Debug(
-					fmt.Sprintf(
-						"Error converting proposer address at height %d: %s",
-						height,
-						err.Error(),
-					),
-				)
-				continue
-			}
-
-			proposerCounts[consAddr]++
-		}
-	}
-
-	log.Debug(
-		fmt.Sprintf(
-			"Scanned blocks %d to %d, found %d unique proposers",
-			startHeight,
-			latestHeight,
-			len(proposerCounts),
-		),
-	)
-
-	return proposerCounts, nil
-}
-
-func AverageBlockTime(ctx context.Context, cfg config.Config, sampleSize int64) (float64, error) {
-	client, err := NewClient(cfg)
-	if err != nil {
-		log.Error(err.Error())
-
-		return 0, endpointError(err.Error())
-	}
-
-	baseClient := base.NewServiceClient(client.conn)
-
-	// Get latest block height first
-	latestReq := &base.GetLatestBlockRequest{}
-	latestResp, err := baseClient.GetLatestBlock(ctx, latestReq)
-
-	defer func() {
-		if tempErr := client.conn.Close(); tempErr != nil {
-			log.Error(tempErr.Error())
-		}
-	}()
-
-	if err != nil {
-		log.Error(err.Error())
-
-		return 0, endpointError(err.Error())
-	}
-
-	latestHeight := latestResp.GetBlock().Header.Height
-	startHeight := latestHeight - sampleSize
-	startHeight = max(startHeight, 1)
-
-	// Get start block
-	startBlockReq := &base.GetBlockByHeightRequest{Height: startHeight}
-	startBlockResp, err := baseClient.GetBlockByHeight(ctx, startBlockReq)
-	if err != nil {
-		log.Error(err.Error())
-
-		return 0, endpointError(err.Error())
-	}
-
-	// Get end block (latest)
-	endBlockReq := &base.GetBlockByHeightRequest{Height: latestHeight}
-	endBlockResp, err := baseClient.GetBlockByHeight(ctx, endBlockReq)
-	if err != nil {
-		log.Error(err.Error())
-
-		return 0, endpointError(err.Error())
-	}
-
-	startTime := startBlockResp.GetBlock().Header.Time.AsTime()
-	endTime := endBlockResp.GetBlock().Header.Time.AsTime()
-
-	timeDiff := endTime.Sub(startTime).Seconds()
-	blockCount := float64(latestHeight - startHeight)
-
-	if blockCount == 0 {
-		return 0, endpointError("invalid block count")
-	}
-
-	avgBlockTime := timeDiff / blockCount
-
-	log.Debug(
-		fmt.Sprintf(
-			"Average block time: %.2f seconds (sampled %d blocks)",
-			avgBlockTime,
-			int64(blockCount),
-		),
-	)
-
-	return avgBlockTime, nil
-}
-
 func bondStatus(status staking.BondStatus) string {
 	statusWithoutPrefix, _ := strings.CutPrefix(status.String(), bondStatusPrefix)
 
@@ -0,0 +1,198 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	auth "cosmossdk.io/api/cosmos/auth/v1beta1"
+	vesting "cosmossdk.io/api/cosmos/vesting/v1beta1"
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	ContinuousVesting = "continuous"
+	DelayedVesting    = "delayed"
+	PeriodicVesting   = "periodic"
+	PermanentlyLocked = "permanent_locked"
+)
+
+// VestingPeriod is a period of a periodic vesting account, its amount vests
+// at the end of the period.
+type VestingPeriod struct {
+	Length time.Duration
+	Amount sdk.Coins
+}
+
+// VestingAccount is the vesting schedule of an account.
+type VestingAccount struct {
+	Type             string
+	OriginalVesting  sdk.Coins
+	DelegatedVesting sdk.Coins
+	// StartTime is zero for delayed and permanently locked accounts.
+	StartTime time.Time
+	// EndTime is zero for permanently locked accounts.
+	EndTime time.Time
+	Periods []VestingPeriod
+}
+
+// VestingAccount returns the vesting schedule of an account. The second return
+// value is false when it is not a vesting account.
+func (c Client) VestingAccount(ctx context.Context, address string) (VestingAccount, bool, error) {
+	client := auth.NewQueryClient(c.conn)
+
+	resp, err := client.Account(ctx, &auth.QueryAccountRequest{Address: address})
+	if err != nil {
+		return VestingAccount{}, false, endpointError(err.Error())
+	}
+
+	account := resp.GetAccount()
+
+	var (
+		continuous = &vesting.ContinuousVestingAccount{}
+		delayed    = &vesting.DelayedVestingAccount{}
+		periodic   = &vesting.PeriodicVestingAccount{}
+		locked     = &vesting.PermanentLockedAccount{}
+		result     *VestingAccount
+	)
+
+	switch {
+	case account.MessageIs(continuous):
+		if err = account.UnmarshalTo(continuous); err != nil {
+			return VestingAccount{}, false, endpointError(err.Error())
+		}
+		result, err = newVestingAccount(ContinuousVesting, continuous.GetBaseVestingAccount())
+		if err == nil {
+			result.StartTime = time.Unix(continuous.GetStartTime(), 0)
+		}
+	case account.MessageIs(delayed):
+		if err = account.UnmarshalTo(delayed); err != nil {
+			return VestingAccount{}, false, endpointError(err.Error())
+		}
+		result, err = newVestingAccount(DelayedVesting, delayed.GetBaseVestingAccount())
+	case account.MessageIs(periodic):
+		if err = account.UnmarshalTo(periodic); err != nil {
+			return VestingAccount{}, false, endpointError(err.Error())
+		}
+		result, err = newVestingAccount(PeriodicVesting, periodic.GetBaseVestingAccount())
+		if err == nil {
+			result.StartTime = time.Unix(periodic.GetStartTime(), 0)
+			result.Periods, err = vestingPeriods(periodic.GetVestingPeriods())
+		}
+	case account.MessageIs(locked):
+		if err = account.UnmarshalTo(locked); err != nil {
+			return VestingAccount{}, false, endpointError(err.Error())
+		}
+		result, err = newVestingAccount(PermanentlyLocked, locked.GetBaseVestingAccount())
+		if err == nil {
+			result.EndTime = time.Time{}
+		}
+	default:
+		return VestingAccount{}, false, nil
+	}
+
+	if err != nil {
+		return VestingAccount{}, false, endpointError(err.Error())
+	}
+
+	return *result, true, nil
+}
+
+func newVestingAccount(accountType string, base *vesting.BaseVestingAccount) (*VestingAccount, error) {
+	originalVesting, err := coins(base.GetOriginalVesting())
+	if err != nil {
+		return nil, err
+	}
+
+	delegatedVesting, err := coins(base.GetDelegatedVesting())
+	if err != nil {
+		return nil, err
+	}
+
+	return &VestingAccount{
+		Type:             accountType,
+		OriginalVesting:  originalVesting,
+		DelegatedVesting: delegatedVesting,
+		EndTime:          time.Unix(base.GetEndTime(), 0),
+	}, nil
+}
+
+func vestingPeriods(periods []*vesting.Period) ([]VestingPeriod, error) {
+	result := make([]VestingPeriod, 0, len(periods))
+	for _, period := range periods {
+		amount, err := coins(period.GetAmount())
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, VestingPeriod{
+			Length: time.Duration(period.GetLength()) * time.Second,
+			Amount: amount,
+		})
+	}
+
+	return result, nil
+}
+
+// Vested returns the amount of denom vested at now, following the rules of
+// the x/auth vesting module.
+func (v VestingAccount) Vested(denom string, now time.Time) math.Int {
+	original := v.OriginalVesting.AmountOf(denom)
+
+	switch v.Type {
+	case ContinuousVesting:
+		if !now.After(v.StartTime) {
+			return math.ZeroInt()
+		}
+		if !now.Before(v.EndTime) {
+			return original
+		}
+
+		elapsed := int64(now.Sub(v.StartTime).Seconds())
+		total := int64(v.EndTime.Sub(v.StartTime).Seconds())
+
+		return original.MulRaw(elapsed).QuoRaw(total)
+	case DelayedVesting:
+		if !now.Before(v.EndTime) {
+			return original
+		}
+
+		return math.ZeroInt()
+	case PeriodicVesting:
+		vested := math.ZeroInt()
+		end := v.StartTime
+		for _, period := range v.Periods {
+			end = end.Add(period.Length)
+			if now.Before(end) {
+				break
+			}
+			vested = vested.Add(period.Amount.AmountOf(denom))
+		}
+
+		return vested
+	default:
+		return math.ZeroInt()
+	}
+}
+
+// NextEvent returns when the next amount vests. Continuous vesting has no
+// discrete events, so its end is returned. The second return value is false
+// when nothing is left to vest.
+func (v VestingAccount) NextEvent(now time.Time) (time.Time, bool) {
+	switch v.Type {
+	case ContinuousVesting, DelayedVesting:
+		if now.Before(v.EndTime) {
+			return v.EndTime, true
+		}
+	case PeriodicVesting:
+		end := v.StartTime
+		for _, period := range v.Periods {
+			end = end.Add(period.Length)
+			if now.Before(end) {
+				return end, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
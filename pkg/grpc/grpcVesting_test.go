@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TestVestingSchedule tests the vested amount and next vesting event of each
+// vesting account type.
+func TestVestingSchedule(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(100 * time.Second)
+	original := sdk.NewCoins(sdk.NewInt64Coin("award", 1000))
+
+	periodic := VestingAccount{
+		Type:            PeriodicVesting,
+		OriginalVesting: original,
+		StartTime:       start,
+		EndTime:         end,
+		Periods: []VestingPeriod{
+			{Length: 50 * time.Second, Amount: sdk.NewCoins(sdk.NewInt64Coin("award", 400))},
+			{Length: 50 * time.Second, Amount: sdk.NewCoins(sdk.NewInt64Coin("award", 600))},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		account VestingAccount
+		now     time.Time
+		vested  int64
+		next    time.Time
+	}{
+		{
+			name:    "continuous halfway",
+			account: VestingAccount{Type: ContinuousVesting, OriginalVesting: original, StartTime: start, EndTime: end},
+			now:     start.Add(25 * time.Second),
+			vested:  250,
+			next:    end,
+		},
+		{
+			name:    "delayed before end",
+			account: VestingAccount{Type: DelayedVesting, OriginalVesting: original, EndTime: end},
+			now:     start,
+			vested:  0,
+			next:    end,
+		},
+		{
+			name:    "delayed after end",
+			account: VestingAccount{Type: DelayedVesting, OriginalVesting: original, EndTime: end},
+			now:     end,
+			vested:  1000,
+		},
+		{
+			name:    "periodic first period",
+			account: periodic,
+			now:     start.Add(10 * time.Second),
+			vested:  0,
+			next:    start.Add(50 * time.Second),
+		},
+		{
+			name:    "periodic second period",
+			account: periodic,
+			now:     start.Add(50 * time.Second),
+			vested:  400,
+			next:    end,
+		},
+		{
+			name:    "permanently locked",
+			account: VestingAccount{Type: PermanentlyLocked, OriginalVesting: original},
+			now:     end,
+			vested:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.account.Vested("award", tt.now); !got.Equal(math.NewInt(tt.vested)) {
+			t.Errorf("%s: Vested() = %s, want %d", tt.name, got, tt.vested)
+		}
+
+		next, ok := tt.account.NextEvent(tt.now)
+		if ok != !tt.next.IsZero() || !next.Equal(tt.next) {
+			t.Errorf("%s: NextEvent() = %s, %v, want %s", tt.name, next, ok, tt.next)
+		}
+	}
+}
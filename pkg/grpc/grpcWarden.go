@@ -2,6 +2,8 @@ package grpc
 
 import (
 	context "context"
+	"encoding/binary"
+	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/types/query"
 	act "github.com/warden-protocol/wardenprotocol/warden/x/act/types/v1beta1"
@@ -9,7 +11,8 @@ import (
 )
 
 const (
-	keyPageLimit = 200000
+	requestPageLimit = 1000
+	keyIDLength      = 8
 )
 
 // spaces metric.
@@ -28,53 +31,86 @@ func (c Client) Spaces(ctx context.Context) (uint64, error) {
 	return spacesRes.Pagination.Total, nil
 }
 
-// keys metric.
-func (c Client) Keys(ctx context.Context) (uint64, uint64, uint64, error) {
-	var (
-		// 	addressTypes []warden.AddressType
-		pendingKeys uint64
-		ecdsaKeys   uint64
-		eddsaKeys   uint64
-		key         []byte
-	)
-
+// AllSpaces returns every space.
+func (c Client) AllSpaces(ctx context.Context) ([]warden.Space, error) {
+	spaces := []warden.Space{}
+	key := []byte{}
 	client := warden.NewQueryClient(c.conn)
 
 	for {
-		req := warden.QueryAllKeysRequest{
-			Pagination: &query.PageRequest{Key: key, Limit: keyPageLimit},
-		}
+		req := warden.QuerySpacesRequest{Pagination: &query.PageRequest{Key: key, Limit: requestPageLimit}}
 
-		allKeys, err := client.AllKeys(ctx, &req)
+		res, err := client.Spaces(ctx, &req)
 		if err != nil {
-			return 0, 0, 0, endpointError(err.Error())
+			return nil, endpointError(err.Error())
 		}
 
-		for _, key := range allKeys.Keys {
-			if key.Key.Type == warden.KeyType_KEY_TYPE_ECDSA_SECP256K1 {
-				ecdsaKeys++
-				continue
-			}
+		spaces = append(spaces, res.GetSpaces()...)
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
 
-			if key.Key.Type == warden.KeyType_KEY_TYPE_EDDSA_ED25519 {
-				eddsaKeys++
-				continue
-			}
+	return spaces, nil
+}
 
-			pendingKeys++
+// KeysPage returns up to limit keys with an id of at least startID, in id
+// order. The second return value is true when more keys follow.
+func (c Client) KeysPage(ctx context.Context, startID uint64, limit uint64) ([]warden.Key, bool, error) {
+	client := warden.NewQueryClient(c.conn)
+
+	// Keys are stored in a collections map keyed by their big-endian encoded
+	// id, so a page can start at any id without a previous next key
+	key := make([]byte, keyIDLength)
+	binary.BigEndian.PutUint64(key, startID)
+
+	req := warden.QueryAllKeysRequest{
+		Pagination: &query.PageRequest{Key: key, Limit: limit},
+	}
+
+	res, err := client.AllKeys(ctx, &req, gogoCodec)
+	if err != nil {
+		return nil, false, endpointError(err.Error())
+	}
+
+	keys := make([]warden.Key, 0, len(res.GetKeys()))
+	for _, k := range res.GetKeys() {
+		keys = append(keys, k.Key)
+	}
+
+	return keys, len(res.GetPagination().GetNextKey()) > 0, nil
+}
+
+// SpaceKeys returns the keys of a space.
+func (c Client) SpaceKeys(ctx context.Context, spaceID uint64) ([]warden.Key, error) {
+	keys := []warden.Key{}
+	key := []byte{}
+	client := warden.NewQueryClient(c.conn)
+
+	for {
+		req := warden.QueryKeysBySpaceIdRequest{
+			SpaceId:    spaceID,
+			Pagination: &query.PageRequest{Key: key, Limit: requestPageLimit},
 		}
 
-		if allKeys.GetPagination() == nil {
-			break
+		res, err := client.KeysBySpaceId(ctx, &req, gogoCodec)
+		if err != nil {
+			return nil, endpointError(err.Error())
 		}
 
-		key = allKeys.Pagination.GetNextKey()
+		for _, k := range res.GetKeys() {
+			keys = append(keys, k.Key)
+		}
+
+		key = res.GetPagination().GetNextKey()
 		if len(key) == 0 {
 			break
 		}
 	}
 
-	return ecdsaKeys, eddsaKeys, pendingKeys, nil
+	return keys, nil
 }
 
 // Keychains metric.
@@ -103,46 +139,184 @@ func (c Client) KeyChain(ctx context.Context, id uint64) (warden.Keychain, error
 	return *keychain.Keychain, nil
 }
 
-func (c Client) KeychainRequests(ctx context.Context, id uint64) (uint64, error) {
-	var key []byte
+// AllKeychains returns every keychain.
+func (c Client) AllKeychains(ctx context.Context) ([]warden.Keychain, error) {
+	keychains := []warden.Keychain{}
+	key := []byte{}
+	client := warden.NewQueryClient(c.conn)
+
+	for {
+		req := warden.QueryKeychainsRequest{Pagination: &query.PageRequest{Key: key, Limit: requestPageLimit}}
+
+		res, err := client.Keychains(ctx, &req)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		keychains = append(keychains, res.GetKeychains()...)
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	return keychains, nil
+}
+
+// PendingKeyRequests returns the pending key requests of a keychain, or of
+// all keychains when id is 0.
+func (c Client) PendingKeyRequests(ctx context.Context, id uint64) ([]*warden.KeyRequest, error) {
+	requests := []*warden.KeyRequest{}
+	key := []byte{}
+	client := warden.NewQueryClient(c.conn)
+
+	for {
+		req := warden.QueryKeyRequestsRequest{
+			KeychainId: id,
+			Status:     warden.KeyRequestStatus_KEY_REQUEST_STATUS_PENDING,
+			Pagination: &query.PageRequest{Key: key, Limit: requestPageLimit},
+		}
+
+		res, err := client.KeyRequests(ctx, &req)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		requests = append(requests, res.GetKeyRequests()...)
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	return requests, nil
+}
+
+// KeyRequestCount returns the number of key requests of a keychain with the
+// given status, without fetching them.
+func (c Client) KeyRequestCount(ctx context.Context, id uint64, status warden.KeyRequestStatus) (uint64, error) {
 	client := warden.NewQueryClient(c.conn)
-	req := warden.QueryKeyRequestsRequest{KeychainId: id, Pagination: &query.PageRequest{Key: key}}
 
-	keychainRequests, err := client.KeyRequests(ctx, &req)
+	req := warden.QueryKeyRequestsRequest{
+		KeychainId: id,
+		Status:     status,
+		Pagination: &query.PageRequest{Limit: 1, CountTotal: true},
+	}
+
+	res, err := client.KeyRequests(ctx, &req)
 	if err != nil {
 		return 0, endpointError(err.Error())
 	}
 
-	return keychainRequests.Pagination.Total, nil
+	return res.GetPagination().GetTotal(), nil
 }
 
-func (c Client) KeychainSignatureRequests(ctx context.Context, id uint64) (uint64, error) {
-	var key []byte
+// KeyRequestStatus returns the status of a key request.
+func (c Client) KeyRequestStatus(ctx context.Context, id uint64) (warden.KeyRequestStatus, error) {
 	client := warden.NewQueryClient(c.conn)
-	req := warden.QuerySignRequestsRequest{KeychainId: id, Pagination: &query.PageRequest{Key: key}}
 
-	keychainRequests, err := client.SignRequests(ctx, &req)
+	res, err := client.KeyRequestById(ctx, &warden.QueryKeyRequestByIdRequest{Id: id})
 	if err != nil {
 		return 0, endpointError(err.Error())
 	}
 
-	return keychainRequests.Pagination.Total, nil
+	return res.GetKeyRequest().GetStatus(), nil
 }
 
-// Actions.
-func (c Client) Actions(ctx context.Context) (uint64, error) {
-	var key []byte
+// PendingSignRequests returns the pending sign requests of a keychain, or of
+// all keychains when id is 0.
+func (c Client) PendingSignRequests(ctx context.Context, id uint64) ([]*warden.SignRequest, error) {
+	requests := []*warden.SignRequest{}
+	key := []byte{}
+	client := warden.NewQueryClient(c.conn)
+
+	for {
+		req := warden.QuerySignRequestsRequest{
+			KeychainId: id,
+			Status:     warden.SignRequestStatus_SIGN_REQUEST_STATUS_PENDING,
+			Pagination: &query.PageRequest{Key: key, Limit: requestPageLimit},
+		}
+
+		res, err := client.SignRequests(ctx, &req)
+		if err != nil {
+			return nil, endpointError(err.Error())
+		}
+
+		requests = append(requests, res.GetSignRequests()...)
+
+		key = res.GetPagination().GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	return requests, nil
+}
+
+// SignRequestCount returns the number of sign requests of a keychain with the
+// given status, without fetching them.
+func (c Client) SignRequestCount(ctx context.Context, id uint64, status warden.SignRequestStatus) (uint64, error) {
+	client := warden.NewQueryClient(c.conn)
+
+	req := warden.QuerySignRequestsRequest{
+		KeychainId: id,
+		Status:     status,
+		Pagination: &query.PageRequest{Limit: 1, CountTotal: true},
+	}
+
+	res, err := client.SignRequests(ctx, &req)
+	if err != nil {
+		return 0, endpointError(err.Error())
+	}
 
+	return res.GetPagination().GetTotal(), nil
+}
+
+// SignRequestStatus returns the status of a sign request.
+func (c Client) SignRequestStatus(ctx context.Context, id uint64) (warden.SignRequestStatus, error) {
+	client := warden.NewQueryClient(c.conn)
+
+	res, err := client.SignRequestById(ctx, &warden.QuerySignRequestByIdRequest{Id: id})
+	if err != nil {
+		return 0, endpointError(err.Error())
+	}
+
+	return res.GetSignRequest().GetStatus(), nil
+}
+
+// ActionsPage returns up to limit x/act actions with an id of at least
+// startID, in id order. The second return value is true when more actions
+// follow.
+func (c Client) ActionsPage(ctx context.Context, startID uint64, limit uint64) ([]act.Action, bool, error) {
 	client := act.NewQueryClient(c.conn)
 
-	req := act.QueryActionsRequest{Pagination: &query.PageRequest{Key: key}}
+	// Actions are stored in a collections map keyed by their big-endian
+	// encoded id, like keys
+	key := make([]byte, keyIDLength)
+	binary.BigEndian.PutUint64(key, startID)
+
+	req := act.QueryActionsRequest{Pagination: &query.PageRequest{Key: key, Limit: limit}}
+
+	res, err := client.Actions(ctx, &req)
+	if err != nil {
+		return nil, false, endpointError(err.Error())
+	}
+
+	return res.GetActions(), len(res.GetPagination().GetNextKey()) > 0, nil
+}
+
+// ActionStatus returns the status of an x/act action.
+func (c Client) ActionStatus(ctx context.Context, id uint64) (act.ActionStatus, error) {
+	client := act.NewQueryClient(c.conn)
 
-	actions, err := client.Actions(ctx, &req)
+	res, err := client.ActionById(ctx, &act.QueryActionByIdRequest{Id: id})
 	if err != nil {
 		return 0, endpointError(err.Error())
 	}
 
-	return actions.Pagination.Total, nil
+	return res.GetAction().GetStatus(), nil
 }
 
 // Rules.
@@ -160,3 +334,19 @@ func (c Client) Rules(ctx context.Context) (uint64, error) {
 
 	return actions.Pagination.Total, nil
 }
+
+// Template returns an x/act template by id.
+func (c Client) Template(ctx context.Context, id uint64) (act.Template, error) {
+	client := act.NewQueryClient(c.conn)
+
+	res, err := client.TemplateById(ctx, &act.QueryTemplateByIdRequest{Id: id})
+	if err != nil {
+		return act.Template{}, endpointError(err.Error())
+	}
+
+	if res.GetTemplate() == nil {
+		return act.Template{}, endpointError(fmt.Sprintf("template %d not found", id))
+	}
+
+	return *res.GetTemplate(), nil
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	warden "github.com/warden-protocol/wardenprotocol/warden/x/warden/types/v1beta3"
+	googlegrpc "google.golang.org/grpc"
+
+	"github.com/warden-protocol/warden-exporter/pkg/config"
+	"github.com/warden-protocol/warden-exporter/pkg/grpc/grpctest"
+)
+
+type wardenKeysServer struct {
+	warden.UnimplementedQueryServer
+	keys []warden.Key
+}
+
+func (s *wardenKeysServer) KeysBySpaceId(
+	_ context.Context,
+	req *warden.QueryKeysBySpaceIdRequest,
+) (*warden.QueryKeysResponse, error) {
+	keys := []warden.Key{}
+	for _, key := range s.keys {
+		if key.SpaceId == req.GetSpaceId() {
+			keys = append(keys, key)
+		}
+	}
+
+	return keysPage(keys, req.GetPagination()), nil
+}
+
+// keysPage returns a page of keys, the next key is the offset of the next
+// page.
+func keysPage(keys []warden.Key, page *query.PageRequest) *warden.QueryKeysResponse {
+	var offset uint64
+	if len(page.GetKey()) == 8 {
+		offset = binary.BigEndian.Uint64(page.GetKey())
+	}
+
+	res := &warden.QueryKeysResponse{Pagination: &query.PageResponse{}}
+	for i := offset; i < uint64(len(keys)); i++ {
+		if uint64(len(res.Keys)) == page.GetLimit() {
+			res.Pagination.NextKey = binary.BigEndian.AppendUint64(nil, i)
+			break
+		}
+		res.Keys = append(res.Keys, warden.QueryKeyResponse{Key: keys[i]})
+	}
+
+	return res
+}
+
+// TestSpaceKeys tests that the keys of a space are paged through a real gRPC
+// server.
+func TestSpaceKeys(t *testing.T) {
+	srv := &wardenKeysServer{}
+	for id := range uint64(requestPageLimit + 1) {
+		srv.keys = append(srv.keys, warden.Key{Id: id + 1, SpaceId: 1})
+	}
+	srv.keys = append(srv.keys, warden.Key{Id: requestPageLimit + 2, SpaceId: 2})
+
+	// The key requests only encode with their gogoproto methods
+	addr := grpctest.NewServer(t, func(server *googlegrpc.Server) {
+		warden.RegisterQueryServer(server, srv)
+	}, grpctest.GogoCodec())
+
+	client, err := NewClient(config.Config{GRPCAddr: addr})
+	if err != nil {
+		t.Fatalf("error creating client: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = client.CloseConn()
+	})
+
+	keys, err := client.SpaceKeys(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("SpaceKeys() error = %s", err)
+	}
+
+	if len(keys) != requestPageLimit+1 {
+		t.Errorf("SpaceKeys() returned %d keys, want %d", len(keys), requestPageLimit+1)
+	}
+}
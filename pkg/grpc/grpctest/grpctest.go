@@ -0,0 +1,38 @@
+// Package grpctest serves gRPC query servers on a local listener for tests.
+package grpctest
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"google.golang.org/grpc"
+)
+
+// NewServer serves the query servers registered by register on a local
+// listener until the test ends and returns the address it listens on.
+func NewServer(t testing.TB, register func(*grpc.Server), opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %s", err)
+	}
+
+	server := grpc.NewServer(opts...)
+	register(server)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// GogoCodec makes a server encode with the gogoproto methods, which the Warden
+// key queries need as their enums cannot be encoded through protobuf
+// reflection.
+func GogoCodec() grpc.ServerOption {
+	return grpc.ForceServerCodec(codec.NewProtoCodec(codectypes.NewInterfaceRegistry()).GRPCCodec())
+}
@@ -11,6 +11,21 @@ import (
 )
 
 func GetRequest(ctx context.Context, url string, apiKey string, timeoutSeconds int) ([]byte, error) {
+	headers := map[string]string{
+		"Authorization": "Bearer " + apiKey,
+	}
+
+	return GetRequestWithHeaders(ctx, url, headers, timeoutSeconds)
+}
+
+// GetRequestWithHeaders performs a GET request for APIs that do not use bearer
+// token authentication, such as the ones expecting an API key header.
+func GetRequestWithHeaders(
+	ctx context.Context,
+	url string,
+	headers map[string]string,
+	timeoutSeconds int,
+) ([]byte, error) {
 	var resp *http.Response
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -22,7 +37,9 @@ func GetRequest(ctx context.Context, url string, apiKey string, timeoutSeconds i
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
-	req.Header.Add("Authorization", "Bearer "+apiKey)
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
 	req.Header.Add("Content-Type", "application/json")
 
 	client := &http.Client{